@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// pygmentsScript tokenises a file with Pygments and prints the tokens as JSON.
+const pygmentsScript = `
+import json, sys
+from pygments.lexers import get_lexer_by_name, get_lexer_for_filename
+alias, path = sys.argv[1], sys.argv[2]
+with open(path, encoding="utf-8") as f:
+    text = f.read()
+if alias:
+    lexer = get_lexer_by_name(alias, stripnl=False)
+else:
+    lexer = get_lexer_for_filename(path, text, stripnl=False)
+json.dump([[str(t), v] for t, v in lexer.get_tokens(text)], sys.stdout)
+`
+
+var (
+	lexerFlag            = flag.String("lexer", "", "Lexer to use for both Chroma and Pygments, instead of matching filenames.")
+	pygmentsLexerFlag    = flag.String("pygments-lexer", "", "Pygments lexer alias, if it differs from --lexer.")
+	pythonFlag           = flag.String("python", "python3", "Python interpreter with Pygments installed.")
+	ignoreWhitespaceFlag = flag.Bool("ignore-whitespace", false, "Ignore type differences in whitespace-only text.")
+	maxDiffsFlag         = flag.Int("max-diffs", 20, "Maximum number of differences to report per file (0 for all).")
+)
+
+func init() {
+	flag.StringVar(lexerFlag, "l", "", "Shorthand for --lexer.")
+}
+
+// span is a run of text with a single token type, in rune offsets.
+type span struct {
+	start, end int
+	tokenType  string
+}
+
+// diff is a run of text that Chroma and Pygments tokenise differently.
+type diff struct {
+	start, end       int
+	chroma, pygments string
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: pygmentscompare [flags] <file>...\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Compare Chroma lexers against Pygments, reporting token-level differences.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			ok, err := compareFile(path)
+			if err != nil {
+				fmt.Printf("%s: error: %s\n", path, err)
+				failed = true
+				return nil
+			}
+			if !ok {
+				failed = true
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pygmentscompare: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// compareFile tokenises path with Chroma and Pygments and prints any differences.
+func compareFile(path string) (bool, error) {
+	var lexer chroma.Lexer
+	if *lexerFlag != "" {
+		lexer = lexers.Get(*lexerFlag)
+	} else {
+		lexer = lexers.Match(path)
+	}
+	if lexer == nil {
+		fmt.Printf("%s: warning: no Chroma lexer, skipping\n", path)
+		return true, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	text := string(data)
+
+	it, err := lexer.Tokenise(nil, text)
+	if err != nil {
+		return false, fmt.Errorf("%s failed to tokenise: %w", lexer.Config().Name, err)
+	}
+	chromaSpans := chromaTokenSpans(it.Tokens())
+
+	pygmentsAlias := *pygmentsLexerFlag
+	if pygmentsAlias == "" {
+		pygmentsAlias = *lexerFlag
+	}
+	pygmentsSpans, err := pygmentsTokenSpans(pygmentsAlias, path)
+	if err != nil {
+		return false, err
+	}
+
+	runes := []rune(text)
+	diffs := compare(chromaSpans, pygmentsSpans, runes, *ignoreWhitespaceFlag)
+	if len(diffs) == 0 {
+		fmt.Printf("%s: ok (%s)\n", path, lexer.Config().Name)
+		return true, nil
+	}
+	differing := 0
+	for _, d := range diffs {
+		differing += d.end - d.start
+	}
+	fmt.Printf("%s: %d differences in %d of %d characters (%s)\n", path, len(diffs), differing, len(runes), lexer.Config().Name)
+	for i, d := range diffs {
+		if *maxDiffsFlag > 0 && i >= *maxDiffsFlag {
+			fmt.Printf("  ... %d more\n", len(diffs)-i)
+			break
+		}
+		line, col := position(runes, d.start)
+		fmt.Printf("  %d:%d: %q chroma=%s pygments=%s\n", line, col, string(runes[d.start:d.end]), d.chroma, d.pygments)
+	}
+	return false, nil
+}
+
+func chromaTokenSpans(tokens []chroma.Token) []span {
+	spans := make([]span, 0, len(tokens))
+	pos := 0
+	for _, token := range tokens {
+		n := len([]rune(token.Value))
+		spans = append(spans, span{pos, pos + n, token.Type.String()})
+		pos += n
+	}
+	return spans
+}
+
+func pygmentsTokenSpans(alias, path string) ([]span, error) {
+	cmd := exec.Command(*pythonFlag, "-c", pygmentsScript, alias, path) // nolint: gosec
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pygments failed: %w", err)
+	}
+	var tokens [][2]string
+	if err := json.Unmarshal(out, &tokens); err != nil {
+		return nil, err
+	}
+	spans := make([]span, 0, len(tokens))
+	pos := 0
+	for _, token := range tokens {
+		n := len([]rune(token[1]))
+		spans = append(spans, span{pos, pos + n, pygmentsTokenType(token[0])})
+		pos += n
+	}
+	return spans, nil
+}
+
+// pygmentsTokenType converts a Pygments token type such as "Token.Literal.String.Double" to
+// the name of the equivalent Chroma token type, "LiteralStringDouble".
+func pygmentsTokenType(name string) string {
+	name = strings.TrimPrefix(name, "Token")
+	name = strings.ReplaceAll(name, ".", "")
+	if name == "" {
+		return chroma.Text.String()
+	}
+	return name
+}
+
+// compare reports the runs of text whose token types differ.
+//
+// Text beyond the end of either token stream is ignored, as Pygments always appends a trailing
+// newline.
+func compare(a, b []span, text []rune, ignoreWhitespace bool) []diff {
+	var diffs []diff
+	i, j, pos := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		end := a[i].end
+		if b[j].end < end {
+			end = b[j].end
+		}
+		if end > len(text) {
+			break
+		}
+		if end > pos && a[i].tokenType != b[j].tokenType &&
+			!(ignoreWhitespace && strings.TrimFunc(string(text[pos:end]), unicode.IsSpace) == "") {
+			last := len(diffs) - 1
+			if last >= 0 && diffs[last].end == pos && diffs[last].chroma == a[i].tokenType && diffs[last].pygments == b[j].tokenType {
+				diffs[last].end = end
+			} else {
+				diffs = append(diffs, diff{pos, end, a[i].tokenType, b[j].tokenType})
+			}
+		}
+		pos = end
+		if a[i].end == end {
+			i++
+		}
+		if b[j].end == end {
+			j++
+		}
+	}
+	return diffs
+}
+
+func position(text []rune, offset int) (line, col int) {
+	line, col = 1, 1
+	for _, r := range text[:offset] {
+		if r == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
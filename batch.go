@@ -0,0 +1,67 @@
+package chroma
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Document is a text to tokenise with BatchTokenise.
+type Document struct {
+	Lexer Lexer
+	Text  string
+	// Options may be nil.
+	Options *TokeniseOptions
+}
+
+// BatchResult is the result of tokenising a Document.
+type BatchResult struct {
+	Tokens []Token
+	Err    error
+}
+
+// BatchTokenise tokenises many documents concurrently, such as the snippets of a static site,
+// with at most runtime.GOMAXPROCS(0) documents tokenised at a time.
+//
+// The results are in the same order as documents. A lexer that fails on one document, including
+// by panicking, only fails that document.
+func BatchTokenise(documents []Document) []BatchResult {
+	results := make([]BatchResult, len(documents))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(documents) {
+		workers = len(documents)
+	}
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = tokeniseDocument(documents[index])
+			}
+		}()
+	}
+	for index := range documents {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func tokeniseDocument(document Document) (result BatchResult) {
+	if document.Lexer == nil {
+		return BatchResult{Err: fmt.Errorf("no lexer for document")}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result = BatchResult{Err: fmt.Errorf("%s: %v", document.Lexer.Config().Name, r)}
+		}
+	}()
+	it, err := document.Lexer.Tokenise(document.Options, document.Text)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+	return BatchResult{Tokens: it.Tokens()}
+}
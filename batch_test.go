@@ -0,0 +1,35 @@
+package chroma
+
+import (
+	"fmt"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestBatchTokenise(t *testing.T) {
+	lexer := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`\s+`, Whitespace, nil},
+			{`\d+`, Number, nil},
+			{`\w+`, Name, nil},
+			{`!`, Text, MutatorFunc(func(state *LexerState) error { return fmt.Errorf("boom") })},
+		},
+	})
+	documents := []Document{}
+	for i := 0; i < 100; i++ {
+		documents = append(documents, Document{Lexer: lexer, Text: fmt.Sprintf("x %d", i)})
+	}
+	documents = append(documents, Document{Lexer: lexer, Text: "x!"}, Document{Text: "x"})
+
+	results := BatchTokenise(documents)
+	assert.Equal(t, len(documents), len(results))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, results[i].Err)
+		assert.Equal(t, []Token{{Name, "x"}, {Whitespace, " "}, {Number, fmt.Sprint(i)}}, results[i].Tokens)
+	}
+	assert.Error(t, results[100].Err)
+	assert.Error(t, results[101].Err)
+
+	assert.Equal(t, 0, len(BatchTokenise(nil)))
+}
@@ -0,0 +1,12 @@
+module github.com/alecthomas/chroma/v2/cmd/chroma-doc
+
+go 1.19
+
+replace github.com/alecthomas/chroma/v2 => ../../
+
+require (
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/alecthomas/kong v1.2.1
+)
+
+require github.com/dlclark/regexp2 v1.11.4 // indirect
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/alecthomas/chroma/v2"
+	htmlformatter "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+var (
+	description = `
+Generate a Markdown or HTML catalogue of all registered Chroma lexers.
+`
+
+	cli struct {
+		Format      string `help:"Output format." enum:"markdown,html" default:"markdown" short:"f"`
+		Output      string `help:"File to write the catalogue to." default:"-" short:"o" type:"path"`
+		Samples     string `help:"Directory of sample sources named <alias>.actual, such as lexers/testdata." type:"existingdir" placeholder:"DIR"`
+		SampleLines int    `help:"Maximum number of lines of each sample to render." default:"20"`
+		Style       string `help:"Style used to render samples in HTML output." default:"github" short:"s"`
+	}
+)
+
+// entry is a lexer and its optional sample source.
+type entry struct {
+	config *chroma.Config
+	lexer  chroma.Lexer
+	sample string
+}
+
+func main() {
+	ctx := kong.Parse(&cli, kong.Description(description))
+
+	entries := catalogue()
+	if cli.Samples != "" {
+		for _, e := range entries {
+			var err error
+			e.sample, err = findSample(cli.Samples, e.config, cli.SampleLines)
+			ctx.FatalIfErrorf(err)
+		}
+	}
+
+	f := os.Stdout
+	if cli.Output != "-" {
+		var err error
+		f, err = os.Create(cli.Output)
+		ctx.FatalIfErrorf(err)
+	}
+	out := bufio.NewWriter(f)
+	var err error
+	if cli.Format == "html" {
+		err = writeHTML(out, entries, styles.Get(cli.Style))
+	} else {
+		err = writeMarkdown(out, entries)
+	}
+	ctx.FatalIfErrorf(err)
+	ctx.FatalIfErrorf(out.Flush())
+	ctx.FatalIfErrorf(f.Close())
+}
+
+// catalogue returns all registered lexers, ordered case-insensitively by name.
+func catalogue() []*entry {
+	entries := make([]*entry, 0, len(lexers.GlobalLexerRegistry.Lexers))
+	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
+		entries = append(entries, &entry{config: lexer.Config(), lexer: lexer})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].config.Name) < strings.ToLower(entries[j].config.Name)
+	})
+	return entries
+}
+
+// findSample reads the first lines of <alias>.actual from dir, trying each alias in turn.
+func findSample(dir string, config *chroma.Config, maxLines int) (string, error) {
+	for _, alias := range config.Aliases {
+		data, err := os.ReadFile(filepath.Join(dir, alias+".actual"))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		lines := strings.SplitAfter(string(data), "\n")
+		if len(lines) > maxLines {
+			lines = lines[:maxLines]
+		}
+		return strings.TrimRight(strings.Join(lines, ""), "\n") + "\n", nil
+	}
+	return "", nil
+}
+
+func writeMarkdown(w io.Writer, entries []*entry) error {
+	fmt.Fprintf(w, "# Lexers\n\nChroma supports %d languages.\n", len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(w, "\n## %s\n\n", e.config.Name)
+		for _, field := range fields(e.config) {
+			quoted := make([]string, len(field.values))
+			for i, value := range field.values {
+				quoted[i] = "`" + value + "`"
+			}
+			fmt.Fprintf(w, "- **%s:** %s\n", field.name, strings.Join(quoted, ", "))
+		}
+		if e.sample != "" {
+			fence := markdownFence(e.sample)
+			alias := ""
+			if len(e.config.Aliases) > 0 {
+				alias = e.config.Aliases[0]
+			}
+			fmt.Fprintf(w, "\n%s%s\n%s%s\n", fence, alias, e.sample, fence)
+		}
+	}
+	return nil
+}
+
+var backtickRunRe = regexp.MustCompile("`+")
+
+// markdownFence returns a code fence longer than any run of backticks in source.
+func markdownFence(source string) string {
+	n := 3
+	for _, run := range backtickRunRe.FindAllString(source, -1) {
+		if len(run) >= n {
+			n = len(run) + 1
+		}
+	}
+	return strings.Repeat("`", n)
+}
+
+func writeHTML(w io.Writer, entries []*entry, style *chroma.Style) error {
+	formatter := htmlformatter.New(htmlformatter.WithClasses(true), htmlformatter.TabWidth(4))
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Chroma lexers</title>\n<style>\n")
+	if err := formatter.WriteCSS(w, style); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "</style>\n</head>\n<body>\n<h1>Lexers</h1>\n<p>Chroma supports %d languages.</p>\n<ul>\n", len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(w, "<li><a href=\"#%s\">%s</a></li>\n", html.EscapeString(url.PathEscape(anchor(e.config.Name))), html.EscapeString(e.config.Name))
+	}
+	fmt.Fprintf(w, "</ul>\n")
+	for _, e := range entries {
+		fmt.Fprintf(w, "<section id=\"%s\">\n<h2>%s</h2>\n<dl>\n", html.EscapeString(anchor(e.config.Name)), html.EscapeString(e.config.Name))
+		for _, field := range fields(e.config) {
+			escaped := make([]string, len(field.values))
+			for i, value := range field.values {
+				escaped[i] = "<code>" + html.EscapeString(value) + "</code>"
+			}
+			fmt.Fprintf(w, "<dt>%s</dt><dd>%s</dd>\n", field.name, strings.Join(escaped, ", "))
+		}
+		fmt.Fprintf(w, "</dl>\n")
+		if e.sample != "" {
+			it, err := chroma.Coalesce(e.lexer).Tokenise(nil, e.sample)
+			if err != nil {
+				return fmt.Errorf("%s: %w", e.config.Name, err)
+			}
+			if err := formatter.Format(w, style, it); err != nil {
+				return fmt.Errorf("%s: %w", e.config.Name, err)
+			}
+		}
+		fmt.Fprintf(w, "</section>\n")
+	}
+	fmt.Fprintf(w, "</body>\n</html>\n")
+	return nil
+}
+
+// anchor converts a lexer name into a fragment identifier, which may not contain whitespace.
+func anchor(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+type field struct {
+	name   string
+	values []string
+}
+
+// fields returns the non-empty Config fields to document.
+func fields(config *chroma.Config) []field {
+	out := []field{}
+	for _, f := range []field{
+		{"Aliases", config.Aliases},
+		{"Filenames", config.Filenames},
+		{"Secondary filenames", config.AliasFilenames},
+		{"MIME types", config.MimeTypes},
+	} {
+		if len(f.values) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -5,13 +5,16 @@ go 1.19
 replace github.com/alecthomas/chroma/v2 => ../../
 
 require (
+	github.com/alecthomas/assert/v2 v2.10.0
 	github.com/alecthomas/chroma/v2 v2.14.0
 	github.com/alecthomas/kong v1.2.1
 	github.com/mattn/go-colorable v0.1.13
 	github.com/mattn/go-isatty v0.0.20
+	golang.org/x/sys v0.6.0
 )
 
 require (
+	github.com/alecthomas/repr v0.4.0 // indirect
 	github.com/dlclark/regexp2 v1.11.4 // indirect
-	golang.org/x/sys v0.6.0 // indirect
+	github.com/hexops/gotextdiff v1.0.3 // indirect
 )
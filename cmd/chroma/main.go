@@ -51,13 +51,18 @@ command, for Go.
 		XML        string           `hidden:"" help:"Generate XML lexer definitions." type:"existingdir" placeholder:"DIR"`
 
 		Lexer string `group:"select" help:"Lexer to use when formatting or path to an XML file to load." default:"autodetect" short:"l"`
-		Style string `group:"select" help:"Style to use for formatting or path to an XML file to load." default:"swapoff" short:"s"`
+		Style string `group:"select" help:"Style to use for formatting or path to an XML file to load. Defaults to swapoff, or a variant suiting the terminal's background." short:"s"`
 
 		Formatter string `group:"format" help:"Formatter to use." default:"terminal" short:"f" enum:"${formatters}"`
 		JSON      bool   `group:"format" help:"Convenience flag to use JSON formatter."`
 		HTML      bool   `group:"format" help:"Convenience flag to use HTML formatter."`
 		SVG       bool   `group:"format" help:"Convenience flag to use SVG formatter."`
 
+		TerminalLines      bool   `group:"terminal" help:"Include line numbers in terminal output."`
+		TerminalWrap       int    `group:"terminal" help:"Soft-wrap terminal output at this many columns." placeholder:"WIDTH"`
+		TerminalBackground bool   `group:"terminal" help:"Fill terminal output with the style's background colour."`
+		TerminalHighlight  string `group:"terminal" help:"Highlight these lines in terminal output." placeholder:"N[:M][,...]"`
+
 		HTMLPrefix                string `group:"html" help:"HTML CSS class prefix." placeholder:"PREFIX"`
 		HTMLStyles                bool   `group:"html" help:"Output HTML CSS styles."`
 		HTMLAllStyles             bool   `group:"html" help:"Output all HTML CSS styles, including redundant ones."`
@@ -77,6 +82,9 @@ command, for Go.
 	}
 )
 
+// defaultStyle is used when no style is specified, adapted to the terminal's background.
+const defaultStyle = "swapoff"
+
 type flushableWriter interface {
 	io.Writer
 	Flush() error
@@ -149,9 +157,10 @@ func main() {
 		"styles":     strings.Join(styles.Names(), ","),
 		"formatters": strings.Join(formatters.Names(), ","),
 	}, kong.Groups{
-		"format": "Output format:",
-		"select": "Select lexer and style:",
-		"html":   "HTML formatter options:",
+		"format":   "Output format:",
+		"select":   "Select lexer and style:",
+		"terminal": "Terminal formatter options:",
+		"html":     "HTML formatter options:",
 	})
 	if cli.XML != "" {
 		err := dumpXMLLexerDefinitions(cli.XML)
@@ -267,6 +276,16 @@ func main() {
 }
 
 func selectStyle() (*chroma.Style, error) {
+	if cli.Style == "" {
+		style := styles.Get(defaultStyle)
+		// Only terminal output is displayed against the terminal's background.
+		if strings.HasPrefix(cli.Formatter, "terminal") {
+			if dark, ok := detectDarkBackground(isatty.IsTerminal(os.Stdout.Fd())); ok {
+				style = styles.Variant(defaultStyle, dark)
+			}
+		}
+		return style, nil
+	}
 	style, ok := styles.Registry[cli.Style]
 	if ok {
 		return style, nil
@@ -293,26 +312,31 @@ func configureHTMLFormatter(ctx *kong.Context) {
 		html.WithLinkableLineNumbers(cli.HTMLLinkableLines, "L"),
 	}
 	if len(cli.HTMLHighlight) > 0 {
-		ranges := [][2]int{}
-		for _, span := range strings.Split(cli.HTMLHighlight, ",") {
-			parts := strings.Split(span, ":")
-			if len(parts) > 2 {
-				ctx.Fatalf("range should be N[:M], not %q", span)
-			}
-			start, err := strconv.ParseInt(parts[0], 10, 64)
-			ctx.FatalIfErrorf(err, "min value of range should be integer not %q", parts[0])
-			end := start
-			if len(parts) == 2 {
-				end, err = strconv.ParseInt(parts[1], 10, 64)
-				ctx.FatalIfErrorf(err, "max value of range should be integer not %q", parts[1])
-			}
-			ranges = append(ranges, [2]int{int(start), int(end)})
-		}
-		options = append(options, html.HighlightLines(ranges))
+		options = append(options, html.HighlightLines(parseLineRanges(ctx, cli.HTMLHighlight)))
 	}
 	formatters.Register("html", html.New(options...))
 }
 
+// parseLineRanges parses line ranges in the form N[:M][,...].
+func parseLineRanges(ctx *kong.Context, spans string) [][2]int {
+	ranges := [][2]int{}
+	for _, span := range strings.Split(spans, ",") {
+		parts := strings.Split(span, ":")
+		if len(parts) > 2 {
+			ctx.Fatalf("range should be N[:M], not %q", span)
+		}
+		start, err := strconv.ParseInt(parts[0], 10, 64)
+		ctx.FatalIfErrorf(err, "min value of range should be integer not %q", parts[0])
+		end := start
+		if len(parts) == 2 {
+			end, err = strconv.ParseInt(parts[1], 10, 64)
+			ctx.FatalIfErrorf(err, "max value of range should be integer not %q", parts[1])
+		}
+		ranges = append(ranges, [2]int{int(start), int(end)})
+	}
+	return ranges
+}
+
 func listAll() {
 	fmt.Println("lexers:")
 	sort.Sort(lexers.GlobalLexerRegistry.Lexers)
@@ -385,8 +409,28 @@ func selexer(path, contents string) (lexer chroma.Lexer, err error) {
 	return lexers.Get(cli.Lexer), nil
 }
 
+// ttyColours maps terminal formatter names to the colours passed to formatters.NewTTY.
+var ttyColours = map[string]int{
+	"terminal":    8,
+	"terminal8":   8,
+	"terminal16":  16,
+	"terminal256": 256,
+	"terminal16m": formatters.TrueColour,
+}
+
 func format(ctx *kong.Context, w io.Writer, style *chroma.Style, it chroma.Iterator) {
 	formatter := formatters.Get(cli.Formatter)
+	if colours, ok := ttyColours[cli.Formatter]; ok {
+		options := []formatters.TTYOption{
+			formatters.TTYLineNumbers(cli.TerminalLines),
+			formatters.TTYWrapWidth(cli.TerminalWrap),
+			formatters.TTYBackground(cli.TerminalBackground),
+		}
+		if cli.TerminalHighlight != "" {
+			options = append(options, formatters.TTYHighlightLines(parseLineRanges(ctx, cli.TerminalHighlight)))
+		}
+		formatter = formatters.NewTTY(colours, options...)
+	}
 	err := formatter.Format(w, style, it)
 	ctx.FatalIfErrorf(err)
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// detectDarkBackground determines whether the terminal has a dark background, first from the
+// COLORFGBG environment variable, then, if query is true, by asking the terminal for its
+// background colour.
+func detectDarkBackground(query bool) (dark, ok bool) {
+	if dark, ok := parseCOLORFGBG(os.Getenv("COLORFGBG")); ok {
+		return dark, true
+	}
+	if !query || os.Getenv("TERM") == "dumb" {
+		return false, false
+	}
+	background, ok := queryBackground()
+	if !ok {
+		return false, false
+	}
+	return background.Brightness() < 0.5, true
+}
+
+// parseCOLORFGBG parses the "fg;bg" or "fg;default;bg" form set by rxvt, Konsole and others,
+// where bg is an ANSI colour index.
+func parseCOLORFGBG(value string) (dark, ok bool) {
+	if value == "" {
+		return false, false
+	}
+	fields := strings.Split(value, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	// White and the bright colours other than dark grey are light.
+	return bg < 7 || bg == 8, true
+}
+
+var osc11Re = regexp.MustCompile(`\x1b\]11;rgba?:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})(?:/[0-9a-fA-F]{1,4})?(?:\x07|\x1b\\)`)
+
+// parseOSC11 extracts the colour from a terminal's reply to the OSC 11 background colour query,
+// such as "\x1b]11;rgb:1e1e/1e1e/2e2e\x1b\\", terminated by either ST or BEL.
+func parseOSC11(reply string) (chroma.Colour, bool) {
+	groups := osc11Re.FindStringSubmatch(reply)
+	if groups == nil {
+		return 0, false
+	}
+	var rgb [3]uint8
+	for i, component := range groups[1:] {
+		n, err := strconv.ParseUint(component, 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		// Components have 1 to 4 hex digits, scaled to 8 bits.
+		max := uint64(1)<<(4*len(component)) - 1
+		rgb[i] = uint8((n*255 + max/2) / max)
+	}
+	return chroma.NewColour(rgb[0], rgb[1], rgb[2]), true
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "github.com/alecthomas/chroma/v2"
+
+// queryBackground is unsupported on this platform.
+func queryBackground() (chroma.Colour, bool) { return 0, false }
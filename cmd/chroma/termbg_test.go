@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestParseCOLORFGBG(t *testing.T) {
+	tests := []struct {
+		value    string
+		dark, ok bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"0;7", false, true},
+		{"7;8", true, true},
+		{"15;default;0", true, true},
+		{"0;default;15", false, true},
+		{"", false, false},
+		{"15;", false, false},
+		{"15;default", false, false},
+		{"15;16", false, false},
+		{"15;-1", false, false},
+		{"fg;bg", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			dark, ok := parseCOLORFGBG(test.value)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.dark, dark)
+		})
+	}
+}
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  string
+		colour string
+	}{
+		{"FourDigitsST", "\x1b]11;rgb:1e1e/1e1e/2e2e\x1b\\", "#1e1e2e"},
+		{"FourDigitsBEL", "\x1b]11;rgb:ffff/ffff/ffff\x07", "#ffffff"},
+		{"TwoDigits", "\x1b]11;rgb:1e/1e/2e\x07", "#1e1e2e"},
+		{"OneDigit", "\x1b]11;rgb:f/0/8\x07", "#ff0088"},
+		{"ThreeDigits", "\x1b]11;rgb:fff/000/800\x07", "#ff0080"},
+		{"RGBA", "\x1b]11;rgba:0000/0000/0000/ffff\x07", "#000000"},
+		{"FollowedByDeviceAttributes", "\x1b]11;rgb:0000/0000/0000\x1b\\\x1b[?62;22c", "#000000"},
+		{"Empty", "", ""},
+		{"OnlyDeviceAttributes", "\x1b[?62;22c", ""},
+		{"Unterminated", "\x1b]11;rgb:0000/0000/0000", ""},
+		{"TooManyDigits", "\x1b]11;rgb:0000/0000/00000\x07", ""},
+		{"MissingComponent", "\x1b]11;rgb:0000/0000\x07", ""},
+		{"NotHex", "\x1b]11;rgb:gggg/0000/0000\x07", ""},
+		{"OtherQuery", "\x1b]10;rgb:0000/0000/0000\x07", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colour, ok := parseOSC11(test.reply)
+			assert.Equal(t, test.colour != "", ok)
+			if ok {
+				assert.Equal(t, chroma.MustParseColour(test.colour), colour)
+			}
+		})
+	}
+}
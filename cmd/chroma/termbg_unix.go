@@ -0,0 +1,55 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// queryBackground asks the controlling terminal for its background colour with OSC 11.
+//
+// The query is followed by a primary device attributes request, which all terminals answer,
+// so that terminals not supporting OSC 11 do not have to wait for the read timeout.
+func queryBackground() (chroma.Colour, bool) {
+	fd, err := unix.Open("/dev/tty", unix.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer unix.Close(fd) // nolint: errcheck
+
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return 0, false
+	}
+	raw := *saved
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	// Reads return after at most 100ms without input.
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = 1
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return 0, false
+	}
+	defer unix.IoctlSetTermios(fd, ioctlSetTermios, saved) // nolint: errcheck
+
+	if _, err := unix.Write(fd, []byte("\x1b]11;?\x1b\\\x1b[c")); err != nil {
+		return 0, false
+	}
+	reply := ""
+	buf := make([]byte, 256)
+	for len(reply) < 1024 {
+		n, err := unix.Read(fd, buf)
+		if err != nil || n <= 0 {
+			break
+		}
+		reply += string(buf[:n])
+		// The device attributes reply, "\x1b[?...c", comes last.
+		if i := strings.Index(reply, "\x1b[?"); i >= 0 && strings.Contains(reply[i:], "c") {
+			break
+		}
+	}
+	return parseOSC11(reply)
+}
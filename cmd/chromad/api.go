@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+type highlightRequest struct {
+	Source    string `json:"source"`
+	Lexer     string `json:"lexer"`
+	Style     string `json:"style"`
+	Formatter string `json:"formatter"`
+}
+
+type highlightResponse struct {
+	Error     string `json:"error,omitempty"`
+	Output    string `json:"output,omitempty"`
+	Lexer     string `json:"lexer,omitempty"`
+	Style     string `json:"style,omitempty"`
+	Formatter string `json:"formatter,omitempty"`
+}
+
+// highlightHandler serves the JSON highlighting API, rejecting bodies larger than maxSize bytes.
+func highlightHandler(maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		req := &highlightRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, &highlightResponse{Error: fmt.Sprintf("request body exceeds %d bytes", maxSize)})
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, &highlightResponse{Error: err.Error()})
+			return
+		}
+		rep, err := highlight(req)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, &highlightResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rep)
+	}
+}
+
+func highlight(req *highlightRequest) (*highlightResponse, error) {
+	var lexer chroma.Lexer
+	if req.Lexer != "" {
+		lexer = lexers.Get(req.Lexer)
+		if lexer == nil {
+			return nil, fmt.Errorf("unknown lexer %q", req.Lexer)
+		}
+	} else if lexer = lexers.Analyse(req.Source); lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	style := styles.Fallback
+	if req.Style != "" {
+		var ok bool
+		if style, ok = styles.Registry[req.Style]; !ok {
+			return nil, fmt.Errorf("unknown style %q", req.Style)
+		}
+	}
+
+	formatterName := req.Formatter
+	if formatterName == "" {
+		formatterName = "html"
+	}
+	formatter, ok := formatters.Registry[formatterName]
+	if !ok {
+		return nil, fmt.Errorf("unknown formatter %q, expected one of %s", formatterName, strings.Join(formatters.Names(), ", "))
+	}
+
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, req.Source)
+	if err != nil {
+		return nil, err
+	}
+	buf := &strings.Builder{}
+	if err := formatter.Format(buf, style, tokens); err != nil {
+		return nil, err
+	}
+	return &highlightResponse{
+		Output:    buf.String(),
+		Lexer:     lexer.Config().Name,
+		Style:     style.Name,
+		Formatter: formatterName,
+	}, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, rep *highlightResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(rep)
+}
+
+// rateLimiter is a per-client token bucket limiter.
+type rateLimiter struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	maxClients int
+	clients    map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxTrackedClients bounds memory use. Once it is reached, idle clients are forgotten, and if
+// none are idle the least recently seen client is.
+const maxTrackedClients = 10000
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), maxClients: maxTrackedClients, clients: map[string]*tokenBucket{}}
+}
+
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= l.maxClients {
+			l.prune(now)
+		}
+		if len(l.clients) >= l.maxClients {
+			l.evictLeastRecent()
+		}
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// prune forgets clients whose buckets have refilled completely.
+func (l *rateLimiter) prune(now time.Time) {
+	for client, bucket := range l.clients {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// evictLeastRecent forgets the client seen least recently.
+func (l *rateLimiter) evictLeastRecent() {
+	oldest := ""
+	var last time.Time
+	for client, bucket := range l.clients {
+		if oldest == "" || bucket.last.Before(last) {
+			oldest, last = client, bucket.last
+		}
+	}
+	delete(l.clients, oldest)
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if !l.allow(client, time.Now()) {
+			w.Header().Set("Retry-After", fmt.Sprint(int(math.Ceil(1/l.rate))))
+			writeJSON(w, http.StatusTooManyRequests, &highlightResponse{Error: "rate limit exceeded"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
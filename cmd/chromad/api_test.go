@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name     string
+		requests []time.Duration // Offsets from start.
+		expected []bool
+	}{
+		{"Burst", []time.Duration{0, 0, 0}, []bool{true, true, false}},
+		{"Refill", []time.Duration{0, 0, 0, 500 * time.Millisecond, 500 * time.Millisecond}, []bool{true, true, false, true, false}},
+		{"RefillCappedAtBurst", []time.Duration{0, time.Hour, time.Hour, time.Hour}, []bool{true, true, true, false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newRateLimiter(2, 2)
+			actual := []bool{}
+			for _, offset := range test.requests {
+				actual = append(actual, limiter.allow("client", start.Add(offset)))
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	// Clients are limited independently.
+	limiter := newRateLimiter(1, 1)
+	assert.True(t, limiter.allow("a", start))
+	assert.False(t, limiter.allow("a", start))
+	assert.True(t, limiter.allow("b", start))
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		expected []string
+	}{
+		// Only a full bucket is forgotten.
+		{"NoneRefilled", 0, []string{"drained", "half"}},
+		{"HalfRefilled", 500 * time.Millisecond, []string{"drained"}},
+		{"AllRefilled", time.Second, []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newRateLimiter(2, 2)
+			limiter.clients = map[string]*tokenBucket{
+				"full":    {tokens: 2, last: start},
+				"half":    {tokens: 1, last: start},
+				"drained": {tokens: 0, last: start},
+			}
+			limiter.prune(start.Add(test.elapsed))
+			actual := []string{}
+			for _, client := range []string{"drained", "full", "half"} {
+				if _, ok := limiter.clients[client]; ok {
+					actual = append(actual, client)
+				}
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestRateLimiterMaxClients(t *testing.T) {
+	start := time.Unix(0, 0)
+	limiter := newRateLimiter(1, 1)
+	limiter.maxClients = 2
+	assert.True(t, limiter.allow("a", start))
+	assert.True(t, limiter.allow("b", start.Add(time.Millisecond)))
+	// Neither bucket has refilled, so the least recently seen client is forgotten.
+	assert.True(t, limiter.allow("c", start.Add(2*time.Millisecond)))
+	assert.Equal(t, 2, len(limiter.clients))
+	_, ok := limiter.clients["a"]
+	assert.False(t, ok)
+	assert.False(t, limiter.allow("b", start.Add(3*time.Millisecond)))
+	assert.Equal(t, 2, len(limiter.clients))
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	handler := newRateLimiter(1, 1).middleware(highlightHandler(1024))
+	request := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/highlight", strings.NewReader(`{"source": "x", "lexer": "go"}`))
+		r.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	assert.Equal(t, http.StatusOK, request().Code)
+	w := request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "rate limit exceeded")
+}
+
+func TestHighlightHandlerMaxSize(t *testing.T) {
+	handler := highlightHandler(32)
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"Small", `{"source": "x", "lexer": "go"}`, http.StatusOK},
+		{"TooLarge", `{"source": "` + strings.Repeat("x", 64) + `"}`, http.StatusRequestEntityTooLarge},
+		{"Invalid", `{`, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/highlight", strings.NewReader(test.body)))
+			assert.Equal(t, test.status, w.Code, w.Body.String())
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		})
+	}
+}
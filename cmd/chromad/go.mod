@@ -3,6 +3,7 @@ module github.com/alecthomas/chroma/v2/cmd/chromad
 go 1.19
 
 require (
+	github.com/alecthomas/assert/v2 v2.10.0
 	github.com/alecthomas/chroma/v2 v2.14.0
 	github.com/alecthomas/kong v1.2.1
 	github.com/alecthomas/kong-hcl v1.0.1
@@ -12,10 +13,12 @@ require (
 )
 
 require (
+	github.com/alecthomas/repr v0.4.0 // indirect
 	github.com/dlclark/regexp2 v1.11.4 // indirect
 	github.com/felixge/httpsnoop v1.0.3 // indirect
 	github.com/gorilla/securecookie v1.1.2 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
+	github.com/hexops/gotextdiff v1.0.3 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 )
 
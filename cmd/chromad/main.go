@@ -177,6 +177,10 @@ func main() {
 		Config  kong.ConfigFlag  `help:"Load configuration." placeholder:"FILE"`
 		Bind    string           `help:"HTTP bind address." default:"127.0.0.1:8080"`
 		CSRFKey string           `help:"CSRF key." default:""`
+
+		MaxSize   int64   `help:"Maximum size in bytes of a /highlight request body." default:"1048576"`
+		RateLimit float64 `help:"Sustained /highlight requests per second allowed per client (0 to disable)." default:"10"`
+		RateBurst int     `help:"Maximum burst of /highlight requests per client." default:"20"`
 	}
 	ctx := kong.Parse(&cli, kong.Configuration(konghcl.Loader), kong.Vars{"version": version})
 
@@ -194,7 +198,16 @@ func main() {
 		options = append(options, csrf.Secure(false))
 	}
 
-	root := handlers.CORS()(csrf.Protect([]byte(cli.CSRFKey), options...)(router))
+	// The JSON API is intended for other services, so it is served outside of CSRF protection.
+	var api http.Handler = highlightHandler(cli.MaxSize)
+	if cli.RateLimit > 0 {
+		api = newRateLimiter(cli.RateLimit, cli.RateBurst).middleware(api)
+	}
+	apiRouter := mux.NewRouter()
+	apiRouter.Handle("/highlight", api).Methods("POST")
+	apiRouter.NotFoundHandler = csrf.Protect([]byte(cli.CSRFKey), options...)(router)
+
+	root := handlers.CORS()(apiRouter)
 
 	err := http.ListenAndServe(cli.Bind, root)
 	ctx.FatalIfErrorf(err)
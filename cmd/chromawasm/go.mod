@@ -0,0 +1,16 @@
+module github.com/alecthomas/chroma/v2/cmd/chromawasm
+
+go 1.19
+
+require (
+	github.com/alecthomas/assert/v2 v2.10.0
+	github.com/alecthomas/chroma/v2 v2.14.0
+)
+
+require (
+	github.com/alecthomas/repr v0.4.0 // indirect
+	github.com/dlclark/regexp2 v1.11.4 // indirect
+	github.com/hexops/gotextdiff v1.0.3 // indirect
+)
+
+replace github.com/alecthomas/chroma/v2 => ../../
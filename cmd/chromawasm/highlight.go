@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+var formatter = html.New()
+
+func highlight(source, lang, style string) (out string, err error) {
+	// A panicking lexer would otherwise kill the WebAssembly instance.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("highlighting failed: %v", r)
+		}
+	}()
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		lexer = lexers.Analyse(source)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, source)
+	if err != nil {
+		return "", err
+	}
+	buf := &strings.Builder{}
+	if err := formatter.Format(buf, styles.Get(style), it); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+func TestHighlight(t *testing.T) {
+	out, err := highlight("package main\n", "go", "monokai")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `<span style="color:#f92672">package</span>`)
+
+	// The language is guessed when not given.
+	out, err = highlight("#!/bin/bash\necho hi\n", "", "monokai")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "<span style=\"color:#75715e\">#!/bin/bash\n</span>")
+}
+
+func TestHighlightPanic(t *testing.T) {
+	lexers.Register(chroma.MustNewLexer(&chroma.Config{Name: "chromawasm-panic"}, func() chroma.Rules {
+		return chroma.Rules{
+			"root": {
+				{`.`, chroma.Text, chroma.MutatorFunc(func(state *chroma.LexerState) error { panic("boom") })},
+			},
+		}
+	}))
+	_, err := highlight("x", "chromawasm-panic", "monokai")
+	assert.EqualError(t, err, "highlighting failed: boom")
+}
@@ -0,0 +1,29 @@
+//go:build js && wasm
+
+// Command chromawasm exposes Chroma to JavaScript when compiled to WebAssembly.
+//
+// It registers a global "chroma" object with a highlight(source, lang, style)
+// function returning an HTML string with inline styles. If lang is empty the
+// language is guessed from the source. On failure an Error is returned instead.
+package main
+
+import "syscall/js"
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("highlight", js.FuncOf(func(this js.Value, args []js.Value) any {
+		values := make([]string, 3)
+		for i := range values {
+			if i < len(args) && args[i].Type() == js.TypeString {
+				values[i] = args[i].String()
+			}
+		}
+		out, err := highlight(values[0], values[1], values[2])
+		if err != nil {
+			return js.Global().Get("Error").New(err.Error())
+		}
+		return out
+	}))
+	js.Global().Set("chroma", api)
+	select {}
+}
@@ -89,3 +89,42 @@ func assertInDelta(t *testing.T, expected, actual float64) {
 	const delta = 0.01 // used for brightness and hue comparisons
 	assert.True(t, actual > (expected-delta) && actual < (expected+delta))
 }
+
+func TestColourHSL(t *testing.T) {
+	h, s, l := ParseColour("#ff0000").HSL()
+	assert.Equal(t, [3]float64{0, 1, 0.5}, [3]float64{h, s, l})
+	h, s, l = ParseColour("#808080").HSL()
+	assert.Equal(t, 0.0, h)
+	assert.Equal(t, 0.0, s)
+	assert.True(t, math.Abs(l-0.502) < 0.001)
+	for _, colour := range []string{"#8913af", "#e06c75", "#282c34", "#ffffff", "#000000"} {
+		h, s, l := ParseColour(colour).HSL()
+		assert.Equal(t, colour, NewColourHSL(h, s, l).String())
+	}
+	assert.Equal(t, "#00ff00", ParseColour("#ff0000").RotateHue(120).String())
+	assert.Equal(t, "#0000ff", ParseColour("#ff0000").RotateHue(-120).String())
+	assert.Equal(t, "#808080", ParseColour("#ff0000").WithSaturation(0).WithLightness(0.5).String())
+	assert.Equal(t, Colour(0), Colour(0).RotateHue(90))
+}
+
+func TestColourLab(t *testing.T) {
+	l, a, b := ParseColour("#ffffff").Lab()
+	assert.True(t, math.Abs(l-100) < 0.01 && math.Abs(a) < 0.01 && math.Abs(b) < 0.01)
+	l, a, b = ParseColour("#ff0000").Lab()
+	assert.True(t, math.Abs(l-53.24) < 0.01 && math.Abs(a-80.09) < 0.01 && math.Abs(b-67.20) < 0.01)
+	assert.Equal(t, 0.0, ParseColour("#8913af").LabDistance(ParseColour("#8913af")))
+	assert.True(t, math.Abs(ParseColour("#000000").LabDistance(ParseColour("#ffffff"))-100) < 0.01)
+}
+
+func TestColoursNearest(t *testing.T) {
+	palette := Colours{MustParseColour("#000000"), MustParseColour("#ff0000"), MustParseColour("#0000ff")}
+	assert.Equal(t, MustParseColour("#ff0000"), palette.Nearest(MustParseColour("#e06c75")))
+	assert.Equal(t, MustParseColour("#000000"), palette.Nearest(MustParseColour("#1c1c2c")))
+	assert.Equal(t, Colour(0), Colours{}.Nearest(MustParseColour("#ffffff")))
+}
+
+func TestColourBlend(t *testing.T) {
+	assert.Equal(t, "#800080", ParseColour("#ff0000").Blend(ParseColour("#0000ff"), 0.5).String())
+	assert.Equal(t, "#ff0000", ParseColour("#ff0000").Blend(ParseColour("#0000ff"), 0).String())
+	assert.Equal(t, "#0000ff", ParseColour("#ff0000").Blend(ParseColour("#0000ff"), 2).String())
+}
@@ -0,0 +1,163 @@
+package chroma
+
+import (
+	"math"
+)
+
+// NewColourHSL creates a Colour from a hue in degrees, and saturation and lightness in the
+// range 0.0 to 1.0.
+func NewColourHSL(h, s, l float64) Colour {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = clampUnit(s)
+	l = clampUnit(l)
+	chr := (1 - math.Abs(2*l-1)) * s
+	x := chr * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chr, x, 0
+	case h < 120:
+		r, g, b = x, chr, 0
+	case h < 180:
+		r, g, b = 0, chr, x
+	case h < 240:
+		r, g, b = 0, x, chr
+	case h < 300:
+		r, g, b = x, 0, chr
+	default:
+		r, g, b = chr, 0, x
+	}
+	m := l - chr/2
+	return NewColour(unitToByte(r+m), unitToByte(g+m), unitToByte(b+m))
+}
+
+// HSL returns the hue of the colour in degrees, and its saturation and lightness in the range
+// 0.0 to 1.0.
+func (c Colour) HSL() (h, s, l float64) {
+	r := float64(c.Red()) / 255
+	g := float64(c.Green()) / 255
+	b := float64(c.Blue()) / 255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	delta := max - min
+	if delta == 0 {
+		return 0, 0, l
+	}
+	s = delta / (1 - math.Abs(2*l-1))
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// RotateHue returns a copy of this colour with its hue rotated by the given number of degrees.
+func (c Colour) RotateHue(degrees float64) Colour {
+	if !c.IsSet() {
+		return c
+	}
+	h, s, l := c.HSL()
+	return NewColourHSL(h+degrees, s, l)
+}
+
+// WithSaturation returns a copy of this colour with its HSL saturation set to s.
+func (c Colour) WithSaturation(s float64) Colour {
+	if !c.IsSet() {
+		return c
+	}
+	h, _, l := c.HSL()
+	return NewColourHSL(h, s, l)
+}
+
+// WithLightness returns a copy of this colour with its HSL lightness set to l.
+func (c Colour) WithLightness(l float64) Colour {
+	if !c.IsSet() {
+		return c
+	}
+	h, s, _ := c.HSL()
+	return NewColourHSL(h, s, l)
+}
+
+// Blend returns a mix of this colour and another, where an amount of 0.0 is this colour and 1.0
+// the other.
+func (c Colour) Blend(other Colour, amount float64) Colour {
+	amount = clampUnit(amount)
+	mix := func(a, b uint8) uint8 {
+		return unitToByte((float64(a)*(1-amount) + float64(b)*amount) / 255)
+	}
+	return NewColour(mix(c.Red(), other.Red()), mix(c.Green(), other.Green()), mix(c.Blue(), other.Blue()))
+}
+
+// Lab converts the colour, as sRGB under a D65 white point, to CIELAB.
+func (c Colour) Lab() (l, a, b float64) {
+	r := srgbToLinear(float64(c.Red()) / 255)
+	g := srgbToLinear(float64(c.Green()) / 255)
+	bl := srgbToLinear(float64(c.Blue()) / 255)
+	// Normalised by the D65 reference white.
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*bl) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*bl
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*bl) / 1.08883
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// LabDistance is the perceptual distance (CIE76 ΔE) between this colour and another.
+//
+// It is slower but more accurate than Distance, and a difference of around 2.3 is just
+// noticeable.
+func (c Colour) LabDistance(e2 Colour) float64 {
+	l1, a1, b1 := c.Lab()
+	l2, a2, b2 := e2.Lab()
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// Nearest returns the colour in the set perceptually closest to colour, or an unset colour if
+// the set is empty.
+//
+// Ties are resolved in favour of the colour that appears first.
+func (c Colours) Nearest(colour Colour) Colour {
+	nearest := Colour(0)
+	closest := math.MaxFloat64
+	for _, candidate := range c {
+		if distance := candidate.LabDistance(colour); distance < closest {
+			closest = distance
+			nearest = candidate
+		}
+	}
+	return nearest
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29
+}
+
+func clampUnit(v float64) float64 {
+	return math.Min(math.Max(v, 0), 1)
+}
+
+func unitToByte(v float64) uint8 {
+	return uint8(math.Round(clampUnit(v) * 255))
+}
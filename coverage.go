@@ -0,0 +1,156 @@
+package chroma
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// RuleCoverage records which states and rules of RegexLexers match during tokenisation.
+//
+// Pass it to Tokenise via TokeniseOptions.Coverage, across as many inputs as desired, then
+// use Rules, Uncovered or WriteReport to find rules that never fired. It is safe for
+// concurrent use.
+type RuleCoverage struct {
+	mu   sync.Mutex
+	hits map[*RegexLexer]map[string][]int
+	// Lexers in the order they were first used, to number lexers sharing a name.
+	order []*RegexLexer
+}
+
+// NewRuleCoverage creates a new, empty, RuleCoverage.
+func NewRuleCoverage() *RuleCoverage {
+	return &RuleCoverage{hits: map[*RegexLexer]map[string][]int{}}
+}
+
+// RuleHits is the number of times a single rule matched.
+type RuleHits struct {
+	Lexer string
+	// Instance distinguishes distinct lexers with the same name, such as copies made by
+	// AddPostProcessor, numbered from 0 in the order they were first used.
+	Instance int
+	State    string
+	Rule     int
+	Pattern  string
+	Hits     int
+}
+
+func (c *RuleCoverage) record(lexer *RegexLexer, state string, rule int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	states, ok := c.hits[lexer]
+	if !ok {
+		states = map[string][]int{}
+		for name, rules := range lexer.rules {
+			states[name] = make([]int, len(rules))
+		}
+		c.hits[lexer] = states
+		c.order = append(c.order, lexer)
+	}
+	states[state][rule]++
+}
+
+// Rules returns the hit count of every rule in the lexers that were used, ordered by lexer
+// name, instance, state and rule index.
+//
+// Rules are as compiled, so included states appear inline in the states including them.
+func (c *RuleCoverage) Rules() []RuleHits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := []RuleHits{}
+	instances := map[string]int{}
+	for _, lexer := range c.order {
+		instance := instances[lexer.config.Name]
+		instances[lexer.config.Name]++
+		for state, hits := range c.hits[lexer] {
+			for i, n := range hits {
+				out = append(out, RuleHits{
+					Lexer:    lexer.config.Name,
+					Instance: instance,
+					State:    state,
+					Rule:     i,
+					Pattern:  lexer.rules[state][i].Pattern,
+					Hits:     n,
+				})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Lexer != out[j].Lexer {
+			return out[i].Lexer < out[j].Lexer
+		}
+		if out[i].Instance != out[j].Instance {
+			return out[i].Instance < out[j].Instance
+		}
+		if out[i].State != out[j].State {
+			return out[i].State < out[j].State
+		}
+		return out[i].Rule < out[j].Rule
+	})
+	return out
+}
+
+// Uncovered returns the rules that never matched.
+func (c *RuleCoverage) Uncovered() []RuleHits {
+	out := []RuleHits{}
+	for _, rule := range c.Rules() {
+		if rule.Hits == 0 {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// WriteReport writes a plain text summary of rule coverage per lexer, listing the states in
+// which no rule matched and the rules that never matched.
+//
+// Distinct lexers with the same name are reported separately, numbered from the second.
+func (c *RuleCoverage) WriteReport(w io.Writer) error {
+	rules := c.Rules()
+	for start := 0; start < len(rules); {
+		end := start
+		covered := 0
+		for end < len(rules) && rules[end].Lexer == rules[start].Lexer && rules[end].Instance == rules[start].Instance {
+			if rules[end].Hits > 0 {
+				covered++
+			}
+			end++
+		}
+		lexerRules := rules[start:end]
+		name := lexerRules[0].Lexer
+		if lexerRules[0].Instance > 0 {
+			name += fmt.Sprintf(" #%d", lexerRules[0].Instance+1)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %d/%d rules (%.1f%%)\n", name, covered, len(lexerRules),
+			100*float64(covered)/float64(len(lexerRules))); err != nil {
+			return err
+		}
+		for i := 0; i < len(lexerRules); {
+			state := lexerRules[i].State
+			j := i
+			matched := false
+			for j < len(lexerRules) && lexerRules[j].State == state {
+				matched = matched || lexerRules[j].Hits > 0
+				j++
+			}
+			if !matched {
+				if _, err := fmt.Fprintf(w, "  %s: no rules matched\n", state); err != nil {
+					return err
+				}
+			} else {
+				for _, rule := range lexerRules[i:j] {
+					if rule.Hits > 0 {
+						continue
+					}
+					if _, err := fmt.Fprintf(w, "  %s[%d]: %s\n", rule.State, rule.Rule, rule.Pattern); err != nil {
+						return err
+					}
+				}
+			}
+			i = j
+		}
+		start = end
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package chroma
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestRuleCoverage(t *testing.T) {
+	lexer := mustNewLexer(t, &Config{Name: "Coverage"}, Rules{
+		"root": {
+			{`"`, String, Push("string")},
+			{`\d+`, Number, nil},
+			{`\w+`, Name, nil},
+			{`\s+`, Whitespace, nil},
+		},
+		"string": {
+			{`"`, String, Pop(1)},
+			{`\\.`, StringEscape, nil},
+			{`[^"\\]+`, String, nil},
+		},
+		"unused": {
+			{`.`, Text, nil},
+		},
+	})
+	coverage := NewRuleCoverage()
+	_, err := Tokenise(lexer, &TokeniseOptions{State: "root", Coverage: coverage}, `foo "bar"`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []RuleHits{
+		{Lexer: "Coverage", State: "root", Rule: 1, Pattern: `\d+`},
+		{Lexer: "Coverage", State: "string", Rule: 1, Pattern: `\\.`},
+		{Lexer: "Coverage", State: "unused", Rule: 0, Pattern: `.`},
+	}, coverage.Uncovered())
+	assert.Equal(t, RuleHits{Lexer: "Coverage", State: "root", Rule: 0, Pattern: `"`, Hits: 1}, coverage.Rules()[0])
+
+	report := &strings.Builder{}
+	assert.NoError(t, coverage.WriteReport(report))
+	assert.Equal(t, "Coverage: 5/8 rules (62.5%)\n"+
+		"  root[1]: \\d+\n"+
+		"  string[1]: \\\\.\n"+
+		"  unused: no rules matched\n", report.String())
+}
+
+func TestRuleCoverageNested(t *testing.T) {
+	inner := mustNewLexer(t, &Config{Name: "Inner"}, Rules{
+		"root": {
+			{`\d+`, Number, nil},
+			{`\w+`, Name, nil},
+		},
+	})
+	outer := mustNewLexer(t, &Config{Name: "Outer"}, Rules{
+		"root": {
+			{`\{[^}]*\}`, UsingLexer(inner), nil},
+			{`[^{]+`, Text, nil},
+		},
+	})
+	coverage := NewRuleCoverage()
+	_, err := Tokenise(outer, &TokeniseOptions{State: "root", Coverage: coverage}, `{abc}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []RuleHits{
+		{Lexer: "Inner", State: "root", Rule: 0, Pattern: `\d+`},
+		{Lexer: "Outer", State: "root", Rule: 1, Pattern: `[^{]+`},
+	}, coverage.Uncovered())
+}
+
+func TestRuleCoverageSameName(t *testing.T) {
+	first := mustNewLexer(t, &Config{Name: "Same"}, Rules{
+		"root": {
+			{`\d+`, Number, nil},
+			{`\w+`, Name, nil},
+		},
+	})
+	second := mustNewLexer(t, &Config{Name: "Same"}, Rules{
+		"root": {
+			{`\w+`, Name, nil},
+		},
+	})
+	coverage := NewRuleCoverage()
+	for _, lexer := range []Lexer{first, second} {
+		_, err := Tokenise(lexer, &TokeniseOptions{State: "root", Coverage: coverage}, `abc`)
+		assert.NoError(t, err)
+	}
+	report := &strings.Builder{}
+	assert.NoError(t, coverage.WriteReport(report))
+	assert.Equal(t, "Same: 1/2 rules (50.0%)\n"+
+		"  root[0]: \\d+\n"+
+		"Same #2: 1/1 rules (100.0%)\n", report.String())
+	assert.Equal(t, []RuleHits{{Lexer: "Same", State: "root", Rule: 0, Pattern: `\d+`}}, coverage.Uncovered())
+}
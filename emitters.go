@@ -7,6 +7,9 @@ import (
 // An Emitter takes group matches and returns tokens.
 type Emitter interface {
 	// Emit tokens for the given regex groups.
+	//
+	// state is positioned after the match, and gives access to the lexer's stack and the
+	// previously emitted token for context-sensitive emission.
 	Emit(groups []string, state *LexerState) Iterator
 }
 
@@ -146,7 +149,7 @@ func (u *usingByGroup) Emit(groups []string, state *LexerState) Iterator {
 	for i, group := range groups[1:] {
 		if i == u.CodeGroup-1 && sublexer != nil {
 			var err error
-			iterators[i], err = sublexer.Tokenise(nil, groups[u.CodeGroup])
+			iterators[i], err = sublexer.Tokenise(&TokeniseOptions{State: "root", EnsureLF: true, Coverage: state.options.Coverage}, groups[u.CodeGroup])
 			if err != nil {
 				panic(err)
 			}
@@ -161,8 +164,8 @@ func (u *usingByGroup) Emit(groups []string, state *LexerState) Iterator {
 //
 // This Emitter is not serialisable.
 func UsingLexer(lexer Lexer) Emitter {
-	return EmitterFunc(func(groups []string, _ *LexerState) Iterator {
-		it, err := lexer.Tokenise(&TokeniseOptions{State: "root", Nested: true}, groups[0])
+	return EmitterFunc(func(groups []string, state *LexerState) Iterator {
+		it, err := lexer.Tokenise(&TokeniseOptions{State: "root", Nested: true, Coverage: state.options.Coverage}, groups[0])
 		if err != nil {
 			panic(err)
 		}
@@ -184,7 +187,7 @@ func (u *usingEmitter) Emit(groups []string, state *LexerState) Iterator {
 	if lexer == nil {
 		panic(fmt.Sprintf("no such lexer %q", u.Lexer))
 	}
-	it, err := lexer.Tokenise(&TokeniseOptions{State: "root", Nested: true}, groups[0])
+	it, err := lexer.Tokenise(&TokeniseOptions{State: "root", Nested: true, Coverage: state.options.Coverage}, groups[0])
 	if err != nil {
 		panic(err)
 	}
@@ -205,7 +208,7 @@ type usingSelfEmitter struct {
 func (u *usingSelfEmitter) EmitterKind() string { return "usingself" }
 
 func (u *usingSelfEmitter) Emit(groups []string, state *LexerState) Iterator {
-	it, err := state.Lexer.Tokenise(&TokeniseOptions{State: u.State, Nested: true}, groups[0])
+	it, err := state.Lexer.Tokenise(&TokeniseOptions{State: u.State, Nested: true, Coverage: state.options.Coverage}, groups[0])
 	if err != nil {
 		panic(err)
 	}
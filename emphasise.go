@@ -0,0 +1,91 @@
+package chroma
+
+import (
+	"regexp"
+	"sort"
+)
+
+// EmphasiseMatches re-types the text matching re in a token stream as ttype, such as
+// GenericEmph, splitting tokens where necessary. Matches may span several tokens.
+//
+// This lets search tools highlight query matches inside already highlighted code.
+func EmphasiseMatches(it Iterator, re *regexp.Regexp, ttype TokenType) Iterator {
+	tokens := it.Tokens()
+	text := make([]byte, 0, 1024)
+	for _, token := range tokens {
+		text = append(text, token.Value...)
+	}
+	matches := re.FindAllIndex(text, -1)
+	ranges := make([][2]int, 0, len(matches))
+	for _, match := range matches {
+		ranges = append(ranges, [2]int{match[0], match[1]})
+	}
+	return EmphasiseRanges(Literator(tokens...), ranges, ttype)
+}
+
+// EmphasiseRanges re-types the text within the given byte ranges of a token stream as ttype,
+// splitting tokens where necessary.
+//
+// Each range is a half-open [start, end) pair of byte offsets into the concatenated text of the
+// tokens, which must fall on UTF-8 character boundaries. Ranges may be unordered and overlap.
+func EmphasiseRanges(it Iterator, ranges [][2]int, ttype TokenType) Iterator {
+	ranges = mergeRanges(ranges)
+	if len(ranges) == 0 {
+		return it
+	}
+	var out []Token
+	offset := 0
+	r := 0
+	for token := it(); token != EOF; token = it() {
+		base := offset
+		offset += len(token.Value)
+		for start := base; start < offset; {
+			// Skip ranges entirely before the remaining text.
+			for r < len(ranges) && ranges[r][1] <= start {
+				r++
+			}
+			split := offset
+			emphasised := false
+			if r < len(ranges) {
+				if ranges[r][0] <= start {
+					emphasised = true
+					if ranges[r][1] < split {
+						split = ranges[r][1]
+					}
+				} else if ranges[r][0] < split {
+					split = ranges[r][0]
+				}
+			}
+			part := token
+			part.Value = token.Value[start-base : split-base]
+			if emphasised {
+				part.Type = ttype
+			}
+			out = append(out, part)
+			start = split
+		}
+	}
+	return Literator(out...)
+}
+
+// mergeRanges sorts ranges and merges those that overlap or touch, dropping empty ranges.
+func mergeRanges(ranges [][2]int) [][2]int {
+	sorted := make([][2]int, 0, len(ranges))
+	for _, r := range ranges {
+		if r[1] > r[0] {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	merged := sorted[:0]
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && r[0] <= merged[last][1] {
+			if r[1] > merged[last][1] {
+				merged[last][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,44 @@
+package chroma
+
+import (
+	"regexp"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestEmphasiseMatches(t *testing.T) {
+	tokens := []Token{
+		{Type: Keyword, Value: "func"},
+		{Type: Text, Value: " "},
+		{Type: NameFunction, Value: "fooBar"},
+		{Type: Punctuation, Value: "()"},
+	}
+	actual := EmphasiseMatches(Literator(tokens...), regexp.MustCompile(`c fo|Bar\(`), GenericEmph).Tokens()
+	assert.Equal(t, []Token{
+		{Type: Keyword, Value: "fun"},
+		{Type: GenericEmph, Value: "c"},
+		{Type: GenericEmph, Value: " "},
+		{Type: GenericEmph, Value: "fo"},
+		{Type: NameFunction, Value: "o"},
+		{Type: GenericEmph, Value: "Bar"},
+		{Type: GenericEmph, Value: "("},
+		{Type: Punctuation, Value: ")"},
+	}, actual)
+
+	// No matches leaves the stream unchanged.
+	actual = EmphasiseMatches(Literator(tokens...), regexp.MustCompile(`xyz`), GenericEmph).Tokens()
+	assert.Equal(t, tokens, actual)
+}
+
+func TestEmphasiseRanges(t *testing.T) {
+	tokens := []Token{{Type: Keyword, Value: "abcdef"}}
+	actual := EmphasiseRanges(Literator(tokens...), [][2]int{{4, 5}, {1, 2}, {2, 3}, {3, 3}}, GenericStrong).Tokens()
+	assert.Equal(t, []Token{
+		{Type: Keyword, Value: "a"},
+		{Type: GenericStrong, Value: "bc"},
+		{Type: Keyword, Value: "d"},
+		{Type: GenericStrong, Value: "e"},
+		{Type: Keyword, Value: "f"},
+	}, actual)
+}
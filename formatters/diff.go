@@ -0,0 +1,183 @@
+package formatters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// DiffKind is how a line was changed by a diff.
+type DiffKind int
+
+// Kinds of changed lines.
+const (
+	DiffUnchanged DiffKind = iota
+	// DiffAdded lines were inserted.
+	DiffAdded
+	// DiffRemoved lines follow lines that were deleted.
+	DiffRemoved
+	// DiffChanged lines replaced deleted lines.
+	DiffChanged
+)
+
+// diffMarkers are the gutter text and token type of each kind of line. The colour of the token
+// type in the style also tints the background of the line.
+var diffMarkers = map[DiffKind]chroma.Token{
+	DiffUnchanged: {Type: chroma.Text, Value: "  "},
+	DiffAdded:     {Type: chroma.GenericInserted, Value: "+ "},
+	DiffRemoved:   {Type: chroma.GenericDeleted, Value: "− "},
+	DiffChanged:   {Type: chroma.GenericHeading, Value: "~ "},
+}
+
+// diffTint is how far line backgrounds are blended towards the colour of their marker.
+const diffTint = 0.2
+
+// NewDiffOverlay wraps a formatter to render a +/−/~ gutter before each line, and tint the
+// background of changed lines, over normally highlighted code.
+//
+// lines maps 1-based line numbers to how they were changed, such as returned by
+// ParseUnifiedDiff. Consecutive lines of the same kind are formatted together with a tinted
+// style, so the wrapped formatter should produce line-oriented output, as the terminal
+// formatters do.
+func NewDiffOverlay(formatter chroma.Formatter, lines map[int]DiffKind) chroma.Formatter {
+	return chroma.FormatterFunc(func(w io.Writer, style *chroma.Style, it chroma.Iterator) error {
+		styles := map[DiffKind]*chroma.Style{}
+		var run []chroma.Token
+		runKind := DiffUnchanged
+		flush := func() error {
+			if len(run) == 0 {
+				return nil
+			}
+			tinted, ok := styles[runKind]
+			if !ok {
+				var err error
+				if tinted, err = tintStyle(style, runKind); err != nil {
+					return err
+				}
+				styles[runKind] = tinted
+			}
+			err := formatter.Format(w, tinted, chroma.Literator(run...))
+			run = nil
+			return err
+		}
+		for i, line := range chroma.SplitTokensIntoLines(it.Tokens()) {
+			kind := lines[i+1]
+			if kind != runKind {
+				if err := flush(); err != nil {
+					return err
+				}
+				runKind = kind
+			}
+			run = append(run, diffMarkers[kind])
+			run = append(run, line...)
+		}
+		return flush()
+	})
+}
+
+// tintStyle blends the background of style towards the marker colour of kind.
+func tintStyle(style *chroma.Style, kind DiffKind) (*chroma.Style, error) {
+	colour := style.Get(diffMarkers[kind].Type).Colour
+	if kind == DiffUnchanged || !colour.IsSet() {
+		return style, nil
+	}
+	background := style.Get(chroma.Background).Background
+	base := background
+	if !base.IsSet() {
+		// Assume a dark terminal.
+		base = chroma.NewColour(0, 0, 0)
+	}
+	tint := base.Blend(colour, diffTint)
+	entry := style.Get(chroma.Background)
+	entry.Background = tint
+	return style.Builder().Transform(func(entry chroma.StyleEntry) chroma.StyleEntry {
+		if entry.Background == background {
+			entry.Background = tint
+		}
+		return entry
+	}).AddEntry(chroma.Background, entry).Build()
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff returns the changed lines of the new version of a file, from a unified diff
+// of that file such as produced by "git diff".
+//
+// Added lines that directly replace removed lines are DiffChanged, and the line following
+// removed lines that were not replaced is DiffRemoved.
+func ParseUnifiedDiff(r io.Reader) (map[int]DiffKind, error) {
+	lines := map[int]DiffKind{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	files := 0
+	// Position in the new file, and the lines remaining in the current hunk.
+	line, oldLines, newLines := 0, 0, 0
+	removed := 0
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldLines == 0 && newLines == 0 {
+			if removed > 0 {
+				lines[line] = DiffRemoved
+				removed = 0
+			}
+			if strings.HasPrefix(text, "+++ ") {
+				if files++; files > 1 {
+					return nil, fmt.Errorf("diff contains more than one file")
+				}
+			}
+			groups := hunkHeaderRe.FindStringSubmatch(text)
+			if groups == nil {
+				continue
+			}
+			line, _ = strconv.Atoi(groups[2])
+			oldLines, newLines = hunkLength(groups[1]), hunkLength(groups[3])
+			if newLines == 0 {
+				// An empty range starts after the given line.
+				line++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, "+"):
+			if removed > 0 {
+				lines[line] = DiffChanged
+				removed--
+			} else {
+				lines[line] = DiffAdded
+			}
+			line++
+			newLines--
+		case strings.HasPrefix(text, "-"):
+			removed++
+			oldLines--
+		case strings.HasPrefix(text, `\`):
+			// "\ No newline at end of file"
+		default:
+			if removed > 0 {
+				lines[line] = DiffRemoved
+				removed = 0
+			}
+			line++
+			oldLines--
+			newLines--
+		}
+	}
+	if removed > 0 {
+		lines[line] = DiffRemoved
+	}
+	return lines, scanner.Err()
+}
+
+// hunkLength parses the optional length of a hunk range, which defaults to 1.
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
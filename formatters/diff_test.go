@@ -0,0 +1,65 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,6 +1,6 @@
+ package main
+-import "fmt"
++import "os"
+ 
++// main does nothing.
+ func main() {
+-	fmt.Println()
+ }
+@@ -20,2 +21,0 @@ func other() {
+-	a()
+-	b()
+\ No newline at end of file
+`
+	lines, err := ParseUnifiedDiff(strings.NewReader(diff))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]DiffKind{
+		2:  DiffChanged,
+		4:  DiffAdded,
+		6:  DiffRemoved,
+		22: DiffRemoved,
+	}, lines)
+
+	_, err = ParseUnifiedDiff(strings.NewReader("--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n--- a/y\n+++ b/y\n"))
+	assert.Error(t, err)
+}
+
+func TestDiffOverlay(t *testing.T) {
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.Background:      "bg:#000000",
+		chroma.GenericInserted: "#00ff00",
+		chroma.Keyword:         "#ff0000",
+	})
+	assert.NoError(t, err)
+	tokens := []chroma.Token{
+		{Type: chroma.Keyword, Value: "if"},
+		{Type: chroma.Text, Value: "\n"},
+		{Type: chroma.Keyword, Value: "else"},
+		{Type: chroma.Text, Value: "\n"},
+	}
+
+	out := &strings.Builder{}
+	formatter := NewDiffOverlay(NewTTY(TrueColour), map[int]DiffKind{2: DiffAdded})
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(tokens...)))
+	assert.Equal(t, "  "+
+		"\033[38;2;255;0;0mif\033[0m\n"+
+		"\033[38;2;0;255;0m\033[48;2;0;51;0m+ \033[0m"+
+		"\033[38;2;255;0;0m\033[48;2;0;51;0melse\033[0m\n", out.String())
+}
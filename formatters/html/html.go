@@ -21,6 +21,18 @@ func Standalone(b bool) Option { return func(f *Formatter) { f.standalone = b }
 // ClassPrefix sets the CSS class prefix.
 func ClassPrefix(prefix string) Option { return func(f *Formatter) { f.prefix = prefix } }
 
+// WithClassMap overrides the CSS classes of token types, so that markup can match an existing
+// stylesheet, such as highlight.js's "hljs-keyword" or Prism's "token keyword".
+//
+// A token type without an entry uses the class of its nearest mapped parent, then falls back to
+// Chroma's standard classes. Mapped classes are used verbatim, without the ClassPrefix, and may
+// contain several space separated names. Mapping a type to "" omits its class. An entry for
+// token type 0 applies to every token without a mapped parent, but not to special types such
+// as Background and LineNumbers.
+func WithClassMap(classes map[chroma.TokenType]string) Option {
+	return func(f *Formatter) { f.classMap = classes }
+}
+
 // WithClasses emits HTML using CSS classes, rather than inline styles.
 func WithClasses(b bool) Option { return func(f *Formatter) { f.Classes = b } }
 
@@ -196,6 +208,7 @@ type Formatter struct {
 	prefix                string
 	Classes               bool // Exported field to detect when classes are being used
 	allClasses            bool
+	classMap              map[chroma.TokenType]string
 	customCSS             map[chroma.TokenType]string
 	preWrapper            PreWrapper
 	inlineCode            bool
@@ -226,17 +239,9 @@ func (f *Formatter) Format(w io.Writer, style *chroma.Style, iterator chroma.Ite
 func (f *Formatter) writeHTML(w io.Writer, style *chroma.Style, tokens []chroma.Token) (err error) { // nolint: gocyclo
 	css := f.styleCache.get(style, true)
 	if f.standalone {
-		fmt.Fprint(w, "<html>\n")
-		if f.Classes {
-			fmt.Fprint(w, "<style type=\"text/css\">\n")
-			err = f.WriteCSS(w, style)
-			if err != nil {
-				return err
-			}
-			fmt.Fprintf(w, "body { %s; }\n", css[chroma.Background])
-			fmt.Fprint(w, "</style>")
+		if err := f.writeStandaloneStart(w, style, css); err != nil {
+			return err
 		}
-		fmt.Fprintf(w, "<body%s>\n", f.styleAttr(css, chroma.Background))
 	}
 
 	wrapInTable := f.lineNumbers && f.lineNumbersInTable
@@ -337,6 +342,21 @@ func (f *Formatter) writeHTML(w io.Writer, style *chroma.Style, tokens []chroma.
 	return nil
 }
 
+// writeStandaloneStart writes the start of a standalone HTML document, up to its body.
+func (f *Formatter) writeStandaloneStart(w io.Writer, style *chroma.Style, css map[chroma.TokenType]string) error {
+	fmt.Fprint(w, "<html>\n")
+	if f.Classes {
+		fmt.Fprint(w, "<style type=\"text/css\">\n")
+		if err := f.WriteCSS(w, style); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "body { %s; }\n", css[chroma.Background])
+		fmt.Fprint(w, "</style>")
+	}
+	fmt.Fprintf(w, "<body%s>\n", f.styleAttr(css, chroma.Background))
+	return nil
+}
+
 func (f *Formatter) lineIDAttribute(line int) string {
 	if !f.linkableLineNumbers {
 		return ""
@@ -372,19 +392,52 @@ func (f *Formatter) shouldHighlight(highlightIndex, line int) (bool, bool) {
 }
 
 func (f *Formatter) class(t chroma.TokenType) string {
+	if f.classMap != nil {
+		for tt := t; tt != 0; tt = tt.Parent() {
+			if cls, ok := f.classMap[tt]; ok {
+				return cls
+			}
+		}
+		// Special types, such as LineNumbers, keep their own classes rather than the fallback.
+		if cls, ok := f.classMap[0]; ok && t >= 0 {
+			return cls
+		}
+	}
 	for t != 0 {
 		if cls, ok := chroma.StandardTypes[t]; ok {
-			if cls != "" {
-				return f.prefix + cls
-			}
-			return ""
+			return f.prefixClass(cls)
 		}
 		t = t.Parent()
 	}
-	if cls := chroma.StandardTypes[t]; cls != "" {
-		return f.prefix + cls
+	return f.prefixClass(chroma.StandardTypes[t])
+}
+
+// prefixClass adds the class prefix to each of the space separated names in cls.
+func (f *Formatter) prefixClass(cls string) string {
+	names := strings.Fields(cls)
+	for i, name := range names {
+		names[i] = f.prefix + name
 	}
-	return ""
+	return strings.Join(names, " ")
+}
+
+// selector returns the CSS class selector for a token type, such as ".token.keyword", or "" if
+// it has no class.
+func (f *Formatter) selector(t chroma.TokenType) string {
+	names := strings.Fields(f.class(t))
+	if len(names) == 0 {
+		return ""
+	}
+	return "." + strings.Join(names, ".")
+}
+
+// descendant returns the selector for elements matching selector within the wrapper, which may
+// be "".
+func descendant(wrapper, selector string) string {
+	if wrapper == "" {
+		return selector
+	}
+	return wrapper + " " + selector
 }
 
 func (f *Formatter) styleAttr(styles map[chroma.TokenType]string, tt chroma.TokenType, extraCSS ...string) string {
@@ -420,17 +473,22 @@ func (f *Formatter) tabWidthStyle() string {
 func (f *Formatter) WriteCSS(w io.Writer, style *chroma.Style) error {
 	css := f.styleCache.get(style, false)
 	// Special-case background as it is mapped to the outer ".chroma" class.
-	if _, err := fmt.Fprintf(w, "/* %s */ .%sbg { %s }\n", chroma.Background, f.prefix, css[chroma.Background]); err != nil {
-		return err
+	if selector := f.selector(chroma.Background); selector != "" {
+		if _, err := fmt.Fprintf(w, "/* %s */ %s { %s }\n", chroma.Background, selector, css[chroma.Background]); err != nil {
+			return err
+		}
 	}
 	// Special-case PreWrapper as it is the ".chroma" class.
-	if _, err := fmt.Fprintf(w, "/* %s */ .%schroma { %s }\n", chroma.PreWrapper, f.prefix, css[chroma.PreWrapper]); err != nil {
-		return err
+	wrapper := f.selector(chroma.PreWrapper)
+	if wrapper != "" {
+		if _, err := fmt.Fprintf(w, "/* %s */ %s { %s }\n", chroma.PreWrapper, wrapper, css[chroma.PreWrapper]); err != nil {
+			return err
+		}
 	}
 	// Special-case code column of table to expand width.
-	if f.lineNumbers && f.lineNumbersInTable {
-		if _, err := fmt.Fprintf(w, "/* %s */ .%schroma .%s:last-child { width: 100%%; }",
-			chroma.LineTableTD, f.prefix, f.class(chroma.LineTableTD)); err != nil {
+	if selector := f.selector(chroma.LineTableTD); f.lineNumbers && f.lineNumbersInTable && selector != "" {
+		if _, err := fmt.Fprintf(w, "/* %s */ %s:last-child { width: 100%%; }",
+			chroma.LineTableTD, descendant(wrapper, selector)); err != nil {
 			return err
 		}
 	}
@@ -438,7 +496,9 @@ func (f *Formatter) WriteCSS(w io.Writer, style *chroma.Style) error {
 	if f.lineNumbers || f.lineNumbersInTable {
 		targetedLineCSS := StyleEntryToCSS(style.Get(chroma.LineHighlight))
 		for _, tt := range []chroma.TokenType{chroma.LineNumbers, chroma.LineNumbersTable} {
-			fmt.Fprintf(w, "/* %s targeted by URL anchor */ .%schroma .%s:target { %s }\n", tt, f.prefix, f.class(tt), targetedLineCSS)
+			if selector := f.selector(tt); selector != "" {
+				fmt.Fprintf(w, "/* %s targeted by URL anchor */ %s:target { %s }\n", tt, descendant(wrapper, selector), targetedLineCSS)
+			}
 		}
 	}
 	tts := []int{}
@@ -452,12 +512,12 @@ func (f *Formatter) WriteCSS(w io.Writer, style *chroma.Style) error {
 		case chroma.Background, chroma.PreWrapper:
 			continue
 		}
-		class := f.class(tt)
-		if class == "" {
+		selector := f.selector(tt)
+		if selector == "" {
 			continue
 		}
 		styles := css[tt]
-		if _, err := fmt.Fprintf(w, "/* %s */ .%schroma .%s { %s }\n", tt, f.prefix, class, styles); err != nil {
+		if _, err := fmt.Fprintf(w, "/* %s */ %s { %s }\n", tt, descendant(wrapper, selector), styles); err != nil {
 			return err
 		}
 	}
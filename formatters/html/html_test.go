@@ -90,6 +90,61 @@ func TestClassPrefix(t *testing.T) {
 	}
 }
 
+func TestWithClassMap(t *testing.T) {
+	f := New(WithClasses(true), ClassPrefix("hljs-"), WithClassMap(map[chroma.TokenType]string{
+		chroma.PreWrapper: "hljs",
+		chroma.Keyword:    "keyword",
+		chroma.String:     "string literal",
+		chroma.Text:       "",
+	}))
+	assert.Equal(t, "keyword", f.class(chroma.KeywordDeclaration))
+	assert.Equal(t, "string literal", f.class(chroma.LiteralStringDouble))
+	assert.Equal(t, "", f.class(chroma.Text))
+	// Unmapped types fall back to standard classes.
+	assert.Equal(t, "hljs-nf", f.class(chroma.NameFunction))
+
+	it, err := lexers.Get("go").Tokenise(nil, `var s = "x"`)
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	err = f.Format(&buf, styles.Fallback, it)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `<pre class="hljs">`)
+	assert.Contains(t, buf.String(), `<span class="keyword">var</span>`)
+	assert.Contains(t, buf.String(), `<span class="string literal">&#34;x&#34;</span>`)
+
+	buf.Reset()
+	err = f.WriteCSS(&buf, styles.Fallback)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "/* LiteralString */ .hljs .string.literal {")
+
+	// Types mapped to "" have no selector, including the wrapper.
+	// The fallback entry does not apply to special types.
+	f = New(WithClasses(true), ClassPrefix("hljs-"), WithClassMap(map[chroma.TokenType]string{
+		0:                  "token",
+		chroma.LineNumbers: "ln",
+	}))
+	assert.Equal(t, "token", f.class(chroma.NameFunction))
+	assert.Equal(t, "ln", f.class(chroma.LineNumbers))
+	assert.Equal(t, "hljs-bg", f.class(chroma.Background))
+	assert.Equal(t, "hljs-hl", f.class(chroma.LineHighlight))
+
+	f = New(WithClasses(true), WithClassMap(map[chroma.TokenType]string{
+		chroma.Background: "",
+		chroma.PreWrapper: "",
+		chroma.Keyword:    "keyword",
+	}))
+	buf.Reset()
+	err = f.WriteCSS(&buf, styles.Fallback)
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), "/* Background */")
+	assert.NotContains(t, buf.String(), "/* PreWrapper */")
+	assert.Contains(t, buf.String(), "/* Keyword */ .keyword {")
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		assert.NotContains(t, line, "*/ . ")
+		assert.NotContains(t, line, "*/  ")
+	}
+}
+
 func TestTableLineNumberNewlines(t *testing.T) {
 	f := New(WithClasses(true), WithLineNumbers(true), LineNumbersInTable(true))
 	it, err := lexers.Get("go").Tokenise(nil, "package main\nfunc main()\n{\nprintln(`hello world`)\n}\n")
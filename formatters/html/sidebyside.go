@@ -0,0 +1,336 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+const (
+	// How far backgrounds are blended towards the GenericDeleted and GenericInserted colours, for
+	// changed lines and for the changed parts of those lines.
+	sideBySideLineTint   = 0.15
+	sideBySideChangeTint = 0.35
+)
+
+// FormatSideBySide writes two versions of a file, such as the old and new versions of a changed
+// file, side by side in a table of aligned, numbered lines.
+//
+// Removed and added lines are tinted with the colours of the style's GenericDeleted and
+// GenericInserted entries, and the parts of changed lines that differ are tinted more strongly.
+func (f *Formatter) FormatSideBySide(w io.Writer, style *chroma.Style, old, new chroma.Iterator) error {
+	css := f.styleCache.get(style, true)
+	oldLines := chroma.SplitTokensIntoLines(old.Tokens())
+	newLines := chroma.SplitTokensIntoLines(new.Tokens())
+	oldText := linesText(oldLines)
+	newText := linesText(newLines)
+	edits := diffEdits(len(oldLines), len(newLines), func(i, j int) bool { return oldText[i] == newText[j] })
+
+	background := style.Get(chroma.Background).Background
+	if !background.IsSet() {
+		background = chroma.MustParseColour("#ffffff")
+	}
+	deleted := style.Get(chroma.GenericDeleted).Colour
+	if !deleted.IsSet() {
+		deleted = chroma.MustParseColour("#ff0000")
+	}
+	inserted := style.Get(chroma.GenericInserted).Colour
+	if !inserted.IsSet() {
+		inserted = chroma.MustParseColour("#00ff00")
+	}
+	oldSide := sideBySideTints{
+		line:   background.Blend(deleted, sideBySideLineTint),
+		change: background.Blend(deleted, sideBySideChangeTint),
+	}
+	newSide := sideBySideTints{
+		line:   background.Blend(inserted, sideBySideLineTint),
+		change: background.Blend(inserted, sideBySideChangeTint),
+	}
+
+	if f.standalone {
+		if err := f.writeStandaloneStart(w, style, css); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "<table%s>\n", f.attrWithCSS(css, chroma.PreWrapper, "border-spacing: 0; width: 100%"))
+	digits := len(fmt.Sprint(len(oldLines)))
+	if n := len(fmt.Sprint(len(newLines))); n > digits {
+		digits = n
+	}
+	writeRow := func(o, n int, changed bool) {
+		var oldChanged, newChanged []bool
+		if changed {
+			oldChanged, newChanged = changedRunes(oldText[o], newText[n])
+		}
+		fmt.Fprint(w, "<tr>")
+		if o >= 0 {
+			f.writeSideBySideCell(w, css, digits, o+1, oldLines[o], oldSide, n < 0 || changed, oldChanged)
+		} else {
+			f.writeSideBySideCell(w, css, digits, 0, nil, oldSide, false, nil)
+		}
+		if n >= 0 {
+			f.writeSideBySideCell(w, css, digits, n+1, newLines[n], newSide, o < 0 || changed, newChanged)
+		} else {
+			f.writeSideBySideCell(w, css, digits, 0, nil, newSide, false, nil)
+		}
+		fmt.Fprint(w, "</tr>\n")
+	}
+	// Removed and added lines between unchanged lines are paired up as changed lines.
+	var removed, added []int
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			switch {
+			case i >= len(removed):
+				writeRow(-1, added[i], false)
+			case i >= len(added):
+				writeRow(removed[i], -1, false)
+			default:
+				writeRow(removed[i], added[i], true)
+			}
+		}
+		removed, added = nil, nil
+	}
+	for _, edit := range edits {
+		switch edit.kind {
+		case editDelete:
+			removed = append(removed, edit.a)
+		case editInsert:
+			added = append(added, edit.b)
+		default:
+			flush()
+			writeRow(edit.a, edit.b, false)
+		}
+	}
+	flush()
+	fmt.Fprint(w, "</table>\n")
+	if f.standalone {
+		fmt.Fprint(w, "\n</body>\n")
+		fmt.Fprint(w, "</html>\n")
+	}
+	return nil
+}
+
+type sideBySideTints struct {
+	line, change chroma.Colour
+}
+
+// writeSideBySideCell writes the line number and code cells of one side of a row. A line number
+// of 0 writes empty cells.
+func (f *Formatter) writeSideBySideCell(w io.Writer, css map[chroma.TokenType]string, digits, line int, tokens []chroma.Token,
+	tints sideBySideTints, tinted bool, changed []bool) {
+	if line == 0 {
+		fmt.Fprintf(w, "<td%s></td><td%s></td>", f.attrWithCSS(css, chroma.LineNumbersTable, ""), f.attrWithCSS(css, chroma.CodeLine, ""))
+		return
+	}
+	codeCSS := "white-space: pre; width: 50%"
+	if tinted {
+		codeCSS += "; background-color: " + tints.line.String()
+	}
+	fmt.Fprintf(w, "<td%s>%*d</td><td%s>", f.attrWithCSS(css, chroma.LineNumbersTable, ""), digits, line, f.attrWithCSS(css, chroma.CodeLine, codeCSS))
+	offset := 0
+	for _, token := range tokens {
+		runes := []rune(strings.TrimSuffix(token.Value, "\n"))
+		out := &strings.Builder{}
+		// Split the token into runs of changed and unchanged text.
+		for start := 0; start < len(runes); {
+			end := start
+			isChanged := offset+start < len(changed) && changed[offset+start]
+			for end < len(runes) && (offset+end < len(changed) && changed[offset+end]) == isChanged {
+				end++
+			}
+			text := html.EscapeString(string(runes[start:end]))
+			if isChanged {
+				fmt.Fprintf(out, `<span style="background-color: %s">%s</span>`, tints.change, text)
+			} else {
+				out.WriteString(text)
+			}
+			start = end
+		}
+		offset += len(runes)
+		if attr := f.styleAttr(css, token.Type); attr != "" && out.Len() > 0 {
+			fmt.Fprintf(w, "<span%s>%s</span>", attr, out)
+		} else {
+			fmt.Fprint(w, out)
+		}
+	}
+	fmt.Fprint(w, "</td>")
+}
+
+// attrWithCSS is like styleAttr, but adds extra inline CSS even when using classes.
+func (f *Formatter) attrWithCSS(css map[chroma.TokenType]string, tt chroma.TokenType, extra string) string {
+	if f.Classes {
+		attr := ""
+		if cls := f.class(tt); cls != "" {
+			attr = fmt.Sprintf(` class="%s"`, cls)
+		}
+		if extra != "" {
+			attr += fmt.Sprintf(` style="%s"`, extra)
+		}
+		return attr
+	}
+	styles := []string{}
+	for _, s := range []string{css[tt], extra} {
+		if s != "" {
+			styles = append(styles, s)
+		}
+	}
+	if len(styles) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(` style="%s"`, strings.Join(styles, ";"))
+}
+
+// linesText returns the text of each line, without its line ending.
+func linesText(lines [][]chroma.Token) []string {
+	out := make([]string, len(lines))
+	for i, tokens := range lines {
+		text := &strings.Builder{}
+		for _, token := range tokens {
+			text.WriteString(token.Value)
+		}
+		out[i] = strings.TrimSuffix(text.String(), "\n")
+	}
+	return out
+}
+
+// changedRunes diffs two versions of a line, reporting which of the runes of each differ.
+func changedRunes(old, new string) (oldChanged, newChanged []bool) {
+	a, b := []rune(old), []rune(new)
+	oldChanged, newChanged = make([]bool, len(a)), make([]bool, len(b))
+	for _, edit := range diffEdits(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }) {
+		switch edit.kind {
+		case editDelete:
+			oldChanged[edit.a] = true
+		case editInsert:
+			newChanged[edit.b] = true
+		}
+	}
+	return oldChanged, newChanged
+}
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is an element of a shortest edit script: an element of the old sequence at index a that
+// is kept as element b of the new sequence, deleted, or an element b inserted.
+type edit struct {
+	kind editKind
+	a, b int
+}
+
+// diffMaxEdits bounds the edit distance searched for by diffEdits, whose memory use is
+// quadratic in the edit distance.
+const diffMaxEdits = 1000
+
+// diffEdits computes an edit script transforming a sequence of n elements into one of m elements.
+//
+// The script is the shortest, found with Myers' algorithm, unless the sequences differ by more
+// than diffMaxEdits edits after removing their common prefix and suffix, in which case the
+// remainder is replaced wholesale.
+func diffEdits(n, m int, equal func(i, j int) bool) []edit {
+	prefix := 0
+	for prefix < n && prefix < m && equal(prefix, prefix) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && equal(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+	edits := make([]edit, 0, n+m-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{editEqual, i, i})
+	}
+	middle, ok := myersEdits(n-prefix-suffix, m-prefix-suffix, func(i, j int) bool { return equal(prefix+i, prefix+j) })
+	if ok {
+		for _, e := range middle {
+			edits = append(edits, edit{e.kind, prefix + e.a, prefix + e.b})
+		}
+	} else {
+		for i := prefix; i < n-suffix; i++ {
+			edits = append(edits, edit{editDelete, i, prefix})
+		}
+		for j := prefix; j < m-suffix; j++ {
+			edits = append(edits, edit{editInsert, n - suffix, j})
+		}
+	}
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, edit{editEqual, n - suffix + i, m - suffix + i})
+	}
+	return edits
+}
+
+// myersEdits computes the shortest edit script using Myers' algorithm, or returns false if it is
+// longer than diffMaxEdits.
+func myersEdits(n, m int, equal func(i, j int) bool) ([]edit, bool) {
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] holds v[k] for k in [-d-1, d+1] at the start of round d.
+	var trace [][]int
+	found := max == 0
+	for d := 0; d <= max && !found; d++ {
+		if d > diffMaxEdits {
+			return nil, false
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && equal(x, y) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prev := trace[d]
+		get := func(k int) int { return prev[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{editEqual, x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{editInsert, x, prevY})
+			} else {
+				edits = append(edits, edit{editDelete, prevX, y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, true
+}
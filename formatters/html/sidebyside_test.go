@@ -0,0 +1,97 @@
+package html
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+func TestDiffEdits(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := make([]byte, rnd.Intn(12))
+		for j := range a {
+			a[j] = byte('a' + rnd.Intn(3))
+		}
+		b := make([]byte, rnd.Intn(12))
+		for j := range b {
+			b[j] = byte('a' + rnd.Intn(3))
+		}
+		var old, new []byte
+		for _, edit := range diffEdits(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }) {
+			switch edit.kind {
+			case editEqual:
+				assert.Equal(t, a[edit.a], b[edit.b])
+				old, new = append(old, a[edit.a]), append(new, b[edit.b])
+			case editDelete:
+				old = append(old, a[edit.a])
+			case editInsert:
+				new = append(new, b[edit.b])
+			}
+		}
+		assert.Equal(t, string(a), string(old))
+		assert.Equal(t, string(b), string(new))
+	}
+	// The script is the shortest.
+	edits := diffEdits(7, 6, func(i, j int) bool { return "ABCABBA"[i] == "CBABAC"[j] })
+	changes := 0
+	for _, edit := range edits {
+		if edit.kind != editEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, 5, changes)
+}
+
+func TestDiffEditsLargeDifferentInput(t *testing.T) {
+	// Entirely different files beyond the edit limit are replaced wholesale, between their
+	// common prefix and suffix.
+	n, m := 50000, 40000
+	equal := func(i, j int) bool { return (i < 10 && j < 10 && i == j) || (n-i == m-j && n-i <= 5) }
+	edits := diffEdits(n, m, equal)
+	assert.Equal(t, n+m-15, len(edits))
+	counts := map[editKind]int{}
+	for i, e := range edits {
+		counts[e.kind]++
+		switch {
+		case i < 10, i >= len(edits)-5:
+			assert.Equal(t, editEqual, e.kind)
+		case i < n-5:
+			assert.Equal(t, edit{editDelete, i, 10}, e)
+		default:
+			assert.Equal(t, editInsert, e.kind)
+		}
+	}
+	assert.Equal(t, map[editKind]int{editEqual: 15, editDelete: n - 15, editInsert: m - 15}, counts)
+}
+
+func TestFormatSideBySide(t *testing.T) {
+	lexer := lexers.Get("go")
+	old, err := lexer.Tokenise(nil, "a := 1\nb := 2\nc := 3\n")
+	assert.NoError(t, err)
+	new, err := lexer.Tokenise(nil, "a := 1\nb := 20\nd := 4\nc := 3\n")
+	assert.NoError(t, err)
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.Background:      "bg:#ffffff",
+		chroma.GenericDeleted:  "#ff0000",
+		chroma.GenericInserted: "#00ff00",
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = New(WithClasses(true)).FormatSideBySide(&buf, style, old, new)
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, `<td class="lnt">1</td><td class="cl" style="white-space: pre; width: 50%"><span class="nx">a</span>`)
+	// The changed line is paired up, with the changed digit tinted.
+	assert.Contains(t, out, `<td class="lnt">2</td><td class="cl" style="white-space: pre; width: 50%; background-color: #ffd9d9">`)
+	assert.Contains(t, out, `<span class="mi">2<span style="background-color: #a6ffa6">0</span></span>`)
+	// The added line has an empty cell opposite.
+	assert.Contains(t, out, `<tr><td class="lnt"></td><td class="cl"></td><td class="lnt">3</td>`)
+	assert.Contains(t, out, `<td class="lnt">3</td><td class="cl" style="white-space: pre; width: 50%"><span class="nx">c</span>`)
+}
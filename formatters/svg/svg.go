@@ -45,6 +45,16 @@ func EmbedFont(fontFamily string, font string, format FontFormat) Option {
 	return func(f *Formatter) { f.fontFamily = fontFamily; f.embeddedFont = font; f.fontFormat = format }
 }
 
+// WithLineNumbers prefixes lines with their numbers, styled with the style's LineNumbers entry.
+func WithLineNumbers(b bool) Option { return func(f *Formatter) { f.lineNumbers = b } }
+
+// HighlightLines highlights the given line ranges with the style's LineHighlight entry.
+//
+// A range is the beginning and ending of a range as 1-based line numbers, inclusive.
+func HighlightLines(ranges [][2]int) Option {
+	return func(f *Formatter) { f.highlightRanges = ranges }
+}
+
 // New SVG formatter.
 func New(options ...Option) *Formatter {
 	f := &Formatter{fontFamily: "Consolas, Monaco, Lucida Console, Liberation Mono, DejaVu Sans Mono, Bitstream Vera Sans Mono, Courier New, monospace"}
@@ -56,9 +66,11 @@ func New(options ...Option) *Formatter {
 
 // Formatter that generates SVG.
 type Formatter struct {
-	fontFamily   string
-	embeddedFont string
-	fontFormat   FontFormat
+	fontFamily      string
+	embeddedFont    string
+	fontFormat      FontFormat
+	lineNumbers     bool
+	highlightRanges [][2]int
 }
 
 func (f *Formatter) Format(w io.Writer, style *chroma.Style, iterator chroma.Iterator) (err error) {
@@ -83,10 +95,14 @@ func escapeString(s string) string {
 func (f *Formatter) writeSVG(w io.Writer, style *chroma.Style, tokens []chroma.Token) { // nolint: gocyclo
 	svgStyles := f.styleToSVG(style)
 	lines := chroma.SplitTokensIntoLines(tokens)
+	gutter := 0
+	if f.lineNumbers {
+		gutter = len(fmt.Sprint(len(lines))) + 1
+	}
 
 	fmt.Fprint(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
 	fmt.Fprint(w, "<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.0//EN\" \"http://www.w3.org/TR/2001/REC-SVG-20010904/DTD/svg10.dtd\">\n")
-	fmt.Fprintf(w, "<svg width=\"%dpx\" height=\"%dpx\" xmlns=\"http://www.w3.org/2000/svg\">\n", 8*maxLineWidth(lines), 10+int(16.8*float64(len(lines)+1)))
+	fmt.Fprintf(w, "<svg width=\"%dpx\" height=\"%dpx\" xmlns=\"http://www.w3.org/2000/svg\">\n", 8*(gutter+maxLineWidth(lines)), 10+int(16.8*float64(len(lines)+1)))
 
 	if f.embeddedFont != "" {
 		f.writeFontStyle(w)
@@ -95,10 +111,15 @@ func (f *Formatter) writeSVG(w io.Writer, style *chroma.Style, tokens []chroma.T
 	fmt.Fprintf(w, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", style.Get(chroma.Background).Background.String())
 	fmt.Fprintf(w, "<g font-family=\"%s\" font-size=\"14px\" fill=\"%s\">\n", f.fontFamily, style.Get(chroma.Text).Colour.String())
 
-	f.writeTokenBackgrounds(w, lines, style)
+	f.writeLineHighlights(w, len(lines), style)
+	f.writeTokenBackgrounds(w, lines, style, gutter)
 
+	lineNumbersAttr := StyleEntryToSVG(style.Get(chroma.LineNumbers))
 	for index, tokens := range lines {
 		fmt.Fprintf(w, "<text x=\"0\" y=\"%fem\" xml:space=\"preserve\">", 1.2*float64(index+1))
+		if f.lineNumbers {
+			fmt.Fprintf(w, "<tspan %s>%s</tspan>", lineNumbersAttr, escapeString(fmt.Sprintf("%*d ", gutter-1, index+1)))
+		}
 
 		for _, token := range tokens {
 			text := escapeString(token.String())
@@ -132,9 +153,9 @@ func maxLineWidth(lines [][]chroma.Token) int {
 // There is no background attribute for text in SVG so simply calculate the position and text
 // of tokens with a background color that differs from the default and add a rectangle for each before
 // adding the token.
-func (f *Formatter) writeTokenBackgrounds(w io.Writer, lines [][]chroma.Token, style *chroma.Style) {
+func (f *Formatter) writeTokenBackgrounds(w io.Writer, lines [][]chroma.Token, style *chroma.Style, gutter int) {
 	for index, tokens := range lines {
-		lineLength := 0
+		lineLength := gutter
 		for _, token := range tokens {
 			length := len(strings.ReplaceAll(token.String(), `	`, "    "))
 			tokenBackground := style.Get(token.Type).Background
@@ -146,6 +167,22 @@ func (f *Formatter) writeTokenBackgrounds(w io.Writer, lines [][]chroma.Token, s
 	}
 }
 
+// writeLineHighlights adds a rectangle in the LineHighlight background colour behind each highlighted line.
+func (f *Formatter) writeLineHighlights(w io.Writer, lines int, style *chroma.Style) {
+	highlight := style.Get(chroma.LineHighlight).Background
+	if !highlight.IsSet() {
+		return
+	}
+	for _, r := range f.highlightRanges {
+		for line := r[0]; line <= r[1] && line <= lines; line++ {
+			if line < 1 {
+				continue
+			}
+			fmt.Fprintf(w, "<rect x=\"0\" y=\"%fem\" width=\"100%%\" height=\"1.2em\" fill=\"%s\" />\n", 1.2*float64(line-1)+0.25, highlight.String())
+		}
+	}
+}
+
 type FontFormat int
 
 // https://transfonter.org/formats
@@ -0,0 +1,28 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestLineNumbersAndHighlight(t *testing.T) {
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.Background:    "#000000 bg:#ffffff",
+		chroma.LineNumbers:   "#808080",
+		chroma.LineHighlight: "bg:#ffff00",
+	})
+	assert.NoError(t, err)
+	source := strings.Repeat("x\n", 10)
+
+	out := &strings.Builder{}
+	err = New(WithLineNumbers(true), HighlightLines([][2]int{{2, 2}})).Format(out, style, chroma.Literator(chroma.Token{Type: chroma.Text, Value: source}))
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `<rect x="0" y="1.450000em" width="100%" height="1.2em" fill="#ffff00" />`)
+	assert.Contains(t, out.String(), `<tspan fill="#808080">&#160;1&#160;</tspan>`)
+	assert.Contains(t, out.String(), `<tspan fill="#808080">10&#160;</tspan>`)
+	assert.Contains(t, out.String(), `<svg width="40px"`)
+}
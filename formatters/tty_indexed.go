@@ -3,7 +3,7 @@ package formatters
 import (
 	"fmt"
 	"io"
-	"math"
+	"sort"
 
 	"github.com/alecthomas/chroma/v2"
 )
@@ -11,6 +11,8 @@ import (
 type ttyTable struct {
 	foreground map[chroma.Colour]string
 	background map[chroma.Colour]string
+	// Colours of the table in ascending order, populated from foreground.
+	palette chroma.Colours
 }
 
 var c = chroma.MustParseColour
@@ -200,17 +202,27 @@ func entryToEscapeSequence(table *ttyTable, entry chroma.StyleEntry) string {
 	return out
 }
 
-func findClosest(table *ttyTable, seeking chroma.Colour) chroma.Colour {
-	closestColour := chroma.Colour(0)
-	closest := float64(math.MaxFloat64)
-	for colour := range table.foreground {
-		distance := colour.Distance(seeking)
-		if distance < closest {
-			closest = distance
-			closestColour = colour
+func init() {
+	for _, table := range ttyTables {
+		for colour := range table.foreground {
+			table.palette = append(table.palette, colour)
 		}
+		sort.Sort(table.palette)
+	}
+}
+
+// TTYPalette returns the colours of the 8, 16 or 256 colour terminal palette, as approximated
+// by the terminal formatters, or nil for any other number of colours.
+func TTYPalette(colours int) chroma.Colours {
+	table, ok := ttyTables[colours]
+	if !ok {
+		return nil
 	}
-	return closestColour
+	return append(chroma.Colours(nil), table.palette...)
+}
+
+func findClosest(table *ttyTable, seeking chroma.Colour) chroma.Colour {
+	return table.palette.Nearest(seeking)
 }
 
 func styleToEscapeSequence(table *ttyTable, style *chroma.Style) map[chroma.TokenType]string {
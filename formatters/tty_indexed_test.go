@@ -1,6 +1,7 @@
 package formatters
 
 import (
+	"sort"
 	"strings"
 	"testing"
 
@@ -10,7 +11,19 @@ import (
 
 func TestClosestColour(t *testing.T) {
 	actual := findClosest(ttyTables[256], chroma.MustParseColour("#e06c75"))
-	assert.Equal(t, chroma.MustParseColour("#d75f87"), actual)
+	assert.Equal(t, chroma.MustParseColour("#d75f5f"), actual)
+	// Dark blue-greys should not be pulled towards saturated blues.
+	actual = findClosest(ttyTables[16], chroma.MustParseColour("#282c34"))
+	assert.Equal(t, chroma.MustParseColour("#000000"), actual)
+}
+
+func TestTTYPalette(t *testing.T) {
+	palette := TTYPalette(16)
+	assert.Equal(t, 16, len(palette))
+	assert.True(t, sort.IsSorted(palette))
+	// Duplicate entries of the 256 colour palette are only listed once.
+	assert.True(t, len(TTYPalette(256)) > 240)
+	assert.Zero(t, TTYPalette(7))
 }
 
 func TestNoneColour(t *testing.T) {
@@ -0,0 +1,270 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// TrueColour can be passed to NewTTY to create a 24-bit colour terminal formatter.
+const TrueColour = 1 << 24
+
+// TTYOption sets an option of a terminal formatter created with NewTTY.
+type TTYOption func(f *ttyFormatter)
+
+// TTYBackground fills lines with the style's Background colour, instead of leaving the terminal's own.
+func TTYBackground(b bool) TTYOption { return func(f *ttyFormatter) { f.background = b } }
+
+// TTYLineNumbers prefixes each line with its number, styled with the style's LineNumbers entry.
+func TTYLineNumbers(b bool) TTYOption { return func(f *ttyFormatter) { f.lineNumbers = b } }
+
+// TTYHighlightLines highlights the given line ranges with the style's LineHighlight entry.
+//
+// A range is the beginning and ending of a range as 1-based line numbers, inclusive.
+func TTYHighlightLines(ranges [][2]int) TTYOption {
+	return func(f *ttyFormatter) { f.highlightRanges = ranges }
+}
+
+// TTYWrapWidth soft-wraps lines longer than width columns, including any line number gutter.
+//
+// Continuation lines keep the colour of the text they continue, and are indented to align with
+// the gutter. A width of 0 disables wrapping.
+func TTYWrapWidth(width int) TTYOption { return func(f *ttyFormatter) { f.wrapWidth = width } }
+
+// NewTTY creates a terminal formatter for 8, 16 or 256 colour terminals, or TrueColour.
+// Other values of colours fall back to 8 colours.
+//
+// Unlike the registered terminal formatters, it can honour the Background, LineNumbers and
+// LineHighlight entries of a style. Error tokens are styled with the Error entry, keeping its
+// background even when TTYBackground is off.
+func NewTTY(colours int, options ...TTYOption) chroma.Formatter {
+	f := &ttyFormatter{}
+	if colours != TrueColour {
+		table, ok := ttyTables[colours]
+		if !ok {
+			table = ttyTables[8]
+		}
+		f.table = table
+	}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+type ttyFormatter struct {
+	// Indexed colour table, or nil for true colour.
+	table           *ttyTable
+	background      bool
+	lineNumbers     bool
+	highlightRanges [][2]int
+	wrapWidth       int
+}
+
+func (f *ttyFormatter) escape(entry chroma.StyleEntry) string {
+	if f.table == nil {
+		return entryToTrueColourEscapeSequence(entry)
+	}
+	return entryToEscapeSequence(f.table, entry)
+}
+
+func (f *ttyFormatter) highlighted(line int) bool {
+	for _, r := range f.highlightRanges {
+		if line >= r[0] && line <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes text styled with entry.
+func (f *ttyFormatter) write(w io.Writer, entry chroma.StyleEntry, text string) error {
+	if text == "" {
+		return nil
+	}
+	escape := f.escape(entry)
+	if escape == "" {
+		_, err := fmt.Fprint(w, text)
+		return err
+	}
+	_, err := fmt.Fprint(w, escape, text, "\033[0m")
+	return err
+}
+
+func (f *ttyFormatter) Format(w io.Writer, style *chroma.Style, it chroma.Iterator) error {
+	if !f.background {
+		style = clearBackground(style)
+	}
+	background := style.Get(chroma.Background).Background
+	highlight := style.Get(chroma.LineHighlight)
+	lines := chroma.SplitTokensIntoLines(it.Tokens())
+	digits := len(fmt.Sprint(len(lines)))
+	for i, tokens := range lines {
+		lineBackground := background
+		highlighted := f.highlighted(i+1) && highlight.Background.IsSet()
+		if highlighted {
+			lineBackground = highlight.Background
+		}
+		gutter := chroma.StyleEntry{}
+		width := 0
+		if f.lineNumbers {
+			gutter = style.Get(chroma.LineNumbers)
+			if highlighted {
+				gutter.Background = lineBackground
+			}
+			if err := f.write(w, gutter, fmt.Sprintf("%*d ", digits, i+1)); err != nil {
+				return err
+			}
+			width = -(digits + 1)
+		}
+		if f.wrapWidth > 0 {
+			// At least one column is left for text, however narrow the terminal.
+			width += f.wrapWidth
+			if width < 1 {
+				width = 1
+			}
+		} else {
+			width = 0
+		}
+		eol := false
+		column := 0
+		for j, token := range tokens {
+			entry := style.Get(token.Type)
+			if highlighted && (!entry.Background.IsSet() || entry.Background == background) {
+				entry.Background = lineBackground
+			}
+			value := token.Value
+			if j == len(tokens)-1 && strings.HasSuffix(value, "\n") {
+				value, eol = strings.TrimSuffix(value, "\n"), true
+			}
+			for {
+				var head string
+				head, value, column = splitAtColumn(value, column, width)
+				if err := f.write(w, entry, head); err != nil {
+					return err
+				}
+				if value == "" {
+					break
+				}
+				// Continue on the next line, past a blank gutter.
+				if err := f.endLine(w, lineBackground); err != nil {
+					return err
+				}
+				if f.lineNumbers {
+					if err := f.write(w, gutter, strings.Repeat(" ", digits+1)); err != nil {
+						return err
+					}
+				}
+				column = 0
+			}
+		}
+		if !eol {
+			continue
+		}
+		if err := f.endLine(w, lineBackground); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endLine fills the rest of the line with its background colour, then starts a new line.
+func (f *ttyFormatter) endLine(w io.Writer, background chroma.Colour) error {
+	if background.IsSet() {
+		if err := f.write(w, chroma.StyleEntry{Background: background}, "\033[K"); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// splitAtColumn splits text where it would extend past width columns, when starting at column.
+// Tabs advance to the next multiple of 8 columns, and other runes by their runeWidth. A width of
+// 0 never splits.
+//
+// At least one rune is kept on an otherwise empty line, so that wrapping always progresses.
+func splitAtColumn(text string, column, width int) (head, rest string, end int) {
+	if width <= 0 {
+		return text, "", column
+	}
+	for i, r := range text {
+		next := column + runeWidth(r)
+		if r == '\t' {
+			next = (column/8 + 1) * 8
+		}
+		if next > width && column > 0 {
+			return text[:i], text[i:], column
+		}
+		column = next
+	}
+	return text, "", column
+}
+
+// wideRanges are the East Asian Wide and Fullwidth ranges of Unicode, including emoji, which
+// terminals display in two columns.
+var wideRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x26aa, 0x26ab, 1},
+		{0x26bd, 0x26be, 1},
+		{0x26c4, 0x26c5, 1},
+		{0x2705, 0x2705, 1},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x2728, 1},
+		{0x274c, 0x274c, 1},
+		{0x2753, 0x2755, 1},
+		{0x2795, 0x2797, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18cff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f004, 1},
+		{0x1f0cf, 0x1f0cf, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f200, 0x1f251, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f7e0, 0x1f7eb, 1},
+		{0x1f90c, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+// runeWidth returns the number of terminal columns r occupies: 2 for wide characters such as
+// CJK and emoji, 0 for combining marks and zero width characters, and otherwise 1.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.Is(wideRanges, r):
+		return 2
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0xfe00 && r <= 0xfe0f:
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,103 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestTTYOptions(t *testing.T) {
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.Background:    "#ffffff bg:#000000",
+		chroma.LineNumbers:   "#808080",
+		chroma.LineHighlight: "bg:#ff0000",
+		chroma.Keyword:       "bold",
+	})
+	assert.NoError(t, err)
+	tokens := []chroma.Token{
+		{Type: chroma.Keyword, Value: "if"},
+		{Type: chroma.Text, Value: " x\n"},
+		{Type: chroma.Text, Value: "y\n"},
+	}
+
+	out := &strings.Builder{}
+	formatter := NewTTY(TrueColour, TTYLineNumbers(true), TTYHighlightLines([][2]int{{2, 2}}))
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(tokens...)))
+	assert.Equal(t, "\033[38;2;128;128;128m1 \033[0m"+
+		"\033[1m\033[38;2;255;255;255mif\033[0m"+
+		"\033[38;2;255;255;255m x\033[0m\n"+
+		"\033[38;2;128;128;128m\033[48;2;255;0;0m2 \033[0m"+
+		"\033[38;2;255;255;255m\033[48;2;255;0;0my\033[0m"+
+		"\033[48;2;255;0;0m\033[K\033[0m\n", out.String())
+
+	out.Reset()
+	formatter = NewTTY(256, TTYBackground(true))
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(tokens[2])))
+	assert.Equal(t, "\033[38;5;231m\033[48;5;16my\033[0m\033[48;5;16m\033[K\033[0m\n", out.String())
+}
+
+func TestTTYWrapWidth(t *testing.T) {
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.LineNumbers: "#808080",
+		chroma.Keyword:     "#ff0000",
+	})
+	assert.NoError(t, err)
+	tokens := []chroma.Token{
+		{Type: chroma.Text, Value: "ab "},
+		{Type: chroma.Keyword, Value: "cdefg"},
+		{Type: chroma.Text, Value: "\n"},
+		{Type: chroma.Text, Value: "xy\n"},
+	}
+
+	out := &strings.Builder{}
+	formatter := NewTTY(TrueColour, TTYLineNumbers(true), TTYWrapWidth(6))
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(tokens...)))
+	assert.Equal(t, "\033[38;2;128;128;128m1 \033[0m"+
+		"ab "+
+		"\033[38;2;255;0;0mc\033[0m\n"+
+		"\033[38;2;128;128;128m  \033[0m"+
+		"\033[38;2;255;0;0mdefg\033[0m\n"+
+		"\033[38;2;128;128;128m2 \033[0m"+
+		"xy\n", out.String())
+
+	// Text too wide for even a single column still progresses.
+	out.Reset()
+	formatter = NewTTY(TrueColour, TTYWrapWidth(1))
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(chroma.Token{Type: chroma.Text, Value: "a\tb\n"})))
+	assert.Equal(t, "a\n\t\nb\n", out.String())
+}
+
+func TestSplitAtColumn(t *testing.T) {
+	head, rest, end := splitAtColumn("abcdef", 2, 4)
+	assert.Equal(t, [3]interface{}{"ab", "cdef", 4}, [3]interface{}{head, rest, end})
+	head, rest, end = splitAtColumn("a\tb", 0, 10)
+	assert.Equal(t, [3]interface{}{"a\tb", "", 9}, [3]interface{}{head, rest, end})
+	head, rest, end = splitAtColumn("abcdef", 3, 0)
+	assert.Equal(t, [3]interface{}{"abcdef", "", 3}, [3]interface{}{head, rest, end})
+}
+
+func TestSplitAtColumnWide(t *testing.T) {
+	head, rest, end := splitAtColumn("漢字漢字", 0, 6)
+	assert.Equal(t, [3]interface{}{"漢字漢", "字", 6}, [3]interface{}{head, rest, end})
+	head, rest, end = splitAtColumn("a漢", 0, 2)
+	assert.Equal(t, [3]interface{}{"a", "漢", 1}, [3]interface{}{head, rest, end})
+	head, rest, end = splitAtColumn("éé", 0, 2)
+	assert.Equal(t, [3]interface{}{"éé", "", 2}, [3]interface{}{head, rest, end})
+	head, rest, end = splitAtColumn("🙂x", 0, 2)
+	assert.Equal(t, [3]interface{}{"🙂", "x", 2}, [3]interface{}{head, rest, end})
+}
+
+func TestTTYErrorEntry(t *testing.T) {
+	style, err := chroma.NewStyle("test", chroma.StyleEntries{
+		chroma.Background: "#ffffff bg:#000000",
+		chroma.Error:      "#ff0000 bg:#00ff00",
+	})
+	assert.NoError(t, err)
+	out := &strings.Builder{}
+	formatter := NewTTY(TrueColour)
+	assert.NoError(t, formatter.Format(out, style, chroma.Literator(chroma.Token{Type: chroma.Error, Value: "?"})))
+	assert.Equal(t, "\033[38;2;255;0;0m\033[48;2;0;255;0m?\033[0m", out.String())
+}
@@ -0,0 +1,17 @@
+module github.com/alecthomas/chroma/v2/goldmark
+
+go 1.19
+
+replace github.com/alecthomas/chroma/v2 => ../
+
+require (
+	github.com/alecthomas/assert/v2 v2.10.0
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/yuin/goldmark v1.7.8
+)
+
+require (
+	github.com/alecthomas/repr v0.4.0 // indirect
+	github.com/dlclark/regexp2 v1.11.4 // indirect
+	github.com/hexops/gotextdiff v1.0.3 // indirect
+)
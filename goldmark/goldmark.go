@@ -0,0 +1,107 @@
+// Package goldmark provides a goldmark extension that highlights fenced code blocks with Chroma.
+//
+//	md := goldmark.New(goldmark.WithExtensions(chromagoldmark.New()))
+package goldmark
+
+import (
+	"bytes"
+	"html"
+
+	gm "github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/alecthomas/chroma/v2"
+	htmlformatter "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Option sets an option of the extension.
+type Option func(e *extension)
+
+// WithStyle sets the name of the style used to highlight code blocks.
+func WithStyle(name string) Option { return func(e *extension) { e.style = styles.Get(name) } }
+
+// WithFormatterOptions sets the options passed to the HTML formatter.
+func WithFormatterOptions(options ...htmlformatter.Option) Option {
+	return func(e *extension) { e.formatterOptions = append(e.formatterOptions, options...) }
+}
+
+// GuessLanguage analyses the content of code blocks without a recognised language.
+func GuessLanguage(b bool) Option { return func(e *extension) { e.guessLanguage = b } }
+
+// New returns a goldmark extension rendering fenced code blocks with Chroma.
+//
+// The lexer is chosen from the first word of the fence info string. Blocks with an
+// unknown or missing language are rendered with the fallback lexer.
+func New(options ...Option) gm.Extender {
+	e := &extension{style: styles.Fallback}
+	for _, option := range options {
+		option(e)
+	}
+	e.formatter = htmlformatter.New(e.formatterOptions...)
+	return e
+}
+
+type extension struct {
+	style            *chroma.Style
+	formatterOptions []htmlformatter.Option
+	formatter        *htmlformatter.Formatter
+	guessLanguage    bool
+}
+
+// Extend implements goldmark.Extender.
+func (e *extension) Extend(m gm.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(e, 200)))
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (e *extension) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, e.renderFencedCodeBlock)
+}
+
+func (e *extension) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.FencedCodeBlock)
+	code := &bytes.Buffer{}
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+	e.highlight(w, e.lexer(string(n.Language(source)), code.String()), code.String())
+	return ast.WalkSkipChildren, nil
+}
+
+// highlight writes code highlighted with lexer, falling back to an unhighlighted block rather
+// than failing the whole document if it can not be highlighted.
+func (e *extension) highlight(w util.BufWriter, lexer chroma.Lexer, code string) {
+	out := &bytes.Buffer{}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err == nil {
+		err = e.formatter.Format(out, e.style, it)
+	}
+	if err != nil {
+		_, _ = w.WriteString("<pre><code>" + html.EscapeString(code) + "</code></pre>\n")
+		return
+	}
+	_, _ = w.Write(out.Bytes())
+}
+
+func (e *extension) lexer(language, code string) chroma.Lexer {
+	if language != "" {
+		if lexer := lexers.Get(language); lexer != nil {
+			return lexer
+		}
+	}
+	if e.guessLanguage {
+		if lexer := lexers.Analyse(code); lexer != nil {
+			return lexer
+		}
+	}
+	return lexers.Fallback
+}
@@ -0,0 +1,52 @@
+package goldmark
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+	gm "github.com/yuin/goldmark"
+
+	"github.com/alecthomas/chroma/v2"
+	htmlformatter "github.com/alecthomas/chroma/v2/formatters/html"
+)
+
+func render(t *testing.T, source string, options ...Option) string {
+	t.Helper()
+	md := gm.New(gm.WithExtensions(New(options...)))
+	out := &bytes.Buffer{}
+	assert.NoError(t, md.Convert([]byte(source), out))
+	return out.String()
+}
+
+func TestFencedCodeBlock(t *testing.T) {
+	out := render(t, "# Title\n\n```go {.numbered}\npackage main\n```\n", WithFormatterOptions(htmlformatter.WithClasses(true)))
+	assert.Contains(t, out, "<h1>Title</h1>")
+	assert.Contains(t, out, `<pre class="chroma">`)
+	assert.Contains(t, out, `<span class="kn">package</span>`)
+}
+
+func TestFencedCodeBlockUnknownLanguage(t *testing.T) {
+	out := render(t, "```nosuchlanguage\n<b>\n```\n", WithFormatterOptions(htmlformatter.WithClasses(true)))
+	assert.Contains(t, out, "&lt;b&gt;")
+}
+
+func TestGuessLanguage(t *testing.T) {
+	source := "```\n#!/bin/bash\necho hi\n```\n"
+	plain := render(t, source, WithFormatterOptions(htmlformatter.WithClasses(true)))
+	guessed := render(t, source, GuessLanguage(true), WithFormatterOptions(htmlformatter.WithClasses(true)))
+	assert.NotEqual(t, plain, guessed)
+}
+
+func TestHighlightFallback(t *testing.T) {
+	broken := chroma.MustNewLexer(&chroma.Config{Name: "Broken"}, func() chroma.Rules {
+		return chroma.Rules{"root": {{Pattern: `(`, Type: chroma.Text}}}
+	})
+	e := New().(*extension)
+	out := &bytes.Buffer{}
+	w := bufio.NewWriter(out)
+	e.highlight(w, broken, "<b>\n")
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "<pre><code>&lt;b&gt;\n</code></pre>\n", out.String())
+}
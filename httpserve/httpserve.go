@@ -0,0 +1,194 @@
+// Package httpserve serves files from an fs.FS as syntax highlighted HTML pages.
+package httpserve
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	htmlformatter "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Options for Handler.
+type Options struct {
+	// Style is the name of the default style. Defaults to styles.Fallback.
+	Style string
+	// StyleParam is the query parameter used to select a style. Defaults to "style".
+	StyleParam string
+	// FormatterOptions are appended to the default HTML formatter options.
+	FormatterOptions []htmlformatter.Option
+}
+
+// Handler returns a http.Handler that serves files from fsys as standalone highlighted HTML pages.
+//
+// Directories are rendered as a plain listing. Responses carry an ETag derived from the file
+// content and the selected style, so unchanged files are answered with 304 Not Modified.
+func Handler(fsys fs.FS, opts Options) http.Handler {
+	if opts.StyleParam == "" {
+		opts.StyleParam = "style"
+	}
+	formatterOptions := append([]htmlformatter.Option{
+		htmlformatter.Standalone(true),
+		htmlformatter.WithLineNumbers(true),
+		htmlformatter.WithLinkableLineNumbers(true, "L"),
+	}, opts.FormatterOptions...)
+	return &handler{
+		fsys:      fsys,
+		opts:      opts,
+		formatter: htmlformatter.New(formatterOptions...),
+	}
+}
+
+type handler struct {
+	fsys      fs.FS
+	opts      Options
+	formatter *htmlformatter.Formatter
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	if info.IsDir() {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			localRedirect(w, r, path.Base(r.URL.Path)+"/")
+			return
+		}
+		h.serveDir(w, r, name)
+		return
+	}
+	source, err := fs.ReadFile(h.fsys, name)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	style := h.style(r)
+	etag := etagFor(source, style.Name)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Analyse(string(source))
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, string(source))
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := h.formatter.Format(buf, style, it); err != nil {
+		h.error(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (h *handler) serveDir(w http.ResponseWriter, r *http.Request, name string) {
+	entries, err := fs.ReadDir(h.fsys, name)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	query := ""
+	if style := r.URL.Query().Get(h.opts.StyleParam); style != "" {
+		query = "?" + url.Values{h.opts.StyleParam: {style}}.Encode()
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<!DOCTYPE html>\n<html>\n<head><title>%s</title></head>\n<body>\n<ul>\n", html.EscapeString(name))
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		href := (&url.URL{Path: entryName}).String() + query
+		fmt.Fprintf(buf, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(href), html.EscapeString(entryName))
+	}
+	buf.WriteString("</ul>\n</body>\n</html>\n")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+// style returns the style selected by the request, falling back to the default style.
+func (h *handler) style(r *http.Request) *chroma.Style {
+	if name := r.URL.Query().Get(h.opts.StyleParam); name != "" {
+		if style, ok := styles.Registry[name]; ok {
+			return style
+		}
+	}
+	return styles.Get(h.opts.Style)
+}
+
+func (h *handler) error(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// localRedirect redirects with a relative Location so the handler works under http.StripPrefix.
+func localRedirect(w http.ResponseWriter, r *http.Request, target string) {
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", target)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+func etagFor(source []byte, style string) string {
+	hash := sha256.New()
+	_, _ = hash.Write(source)
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(style))
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag, using weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
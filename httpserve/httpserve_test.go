@@ -0,0 +1,80 @@
+package httpserve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+var testFS = fstest.MapFS{
+	"main.go":        {Data: []byte("package main\n\nfunc main() {}\n")},
+	"docs/README.md": {Data: []byte("# Title\n")},
+}
+
+func serve(t *testing.T, h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandlerServesHighlightedFile(t *testing.T) {
+	h := Handler(testFS, Options{})
+	w := serve(t, h, http.MethodGet, "/main.go", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<html>")
+	assert.Contains(t, w.Body.String(), `id="L1"`)
+	assert.Contains(t, w.Body.String(), "package")
+	assert.NotEqual(t, "", w.Header().Get("ETag"))
+}
+
+func TestHandlerETag(t *testing.T) {
+	h := Handler(testFS, Options{})
+	etag := serve(t, h, http.MethodGet, "/main.go", nil).Header().Get("ETag")
+
+	w := serve(t, h, http.MethodGet, "/main.go", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "", w.Body.String())
+
+	w = serve(t, h, http.MethodGet, "/main.go?style=monokai", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+func TestHandlerStyleParameter(t *testing.T) {
+	h := Handler(testFS, Options{Style: "monokai", StyleParam: "theme"})
+	monokai := serve(t, h, http.MethodGet, "/main.go", nil)
+	github := serve(t, h, http.MethodGet, "/main.go?theme=github", nil)
+	unknown := serve(t, h, http.MethodGet, "/main.go?theme=missing", nil)
+	assert.NotEqual(t, monokai.Body.String(), github.Body.String())
+	assert.Equal(t, monokai.Body.String(), unknown.Body.String())
+}
+
+func TestHandlerDirectories(t *testing.T) {
+	h := Handler(testFS, Options{})
+	w := serve(t, h, http.MethodGet, "/docs", nil)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "docs/", w.Header().Get("Location"))
+
+	w = serve(t, h, http.MethodGet, "/?style=github", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<a href="docs/?style=github">docs/</a>`)
+	assert.Contains(t, w.Body.String(), `<a href="main.go?style=github">main.go</a>`)
+}
+
+func TestHandlerErrors(t *testing.T) {
+	h := Handler(testFS, Options{})
+	assert.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, "/missing.go", nil).Code)
+	assert.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, "/../main.go/x", nil).Code)
+	w := serve(t, h, http.MethodPost, "/main.go", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+}
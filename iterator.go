@@ -74,3 +74,43 @@ func SplitTokensIntoLines(tokens []Token) (out [][]Token) {
 	}
 	return
 }
+
+// Excerpt returns the lines of tokens from start to end, as 1-based and inclusive line numbers,
+// along with up to context lines either side, such as for embedding highlighted excerpts in
+// error reports or search results.
+//
+// Where lines are omitted before or after the excerpt, a line containing ellipsis as a Comment
+// token is added, unless ellipsis is empty. The line number of the first line of the excerpt,
+// not counting any ellipsis line, is also returned, to number lines from when formatting without
+// an ellipsis.
+func Excerpt(tokens []Token, start, end, context int, ellipsis string) (excerpt []Token, firstLine int) {
+	lines := SplitTokensIntoLines(tokens)
+	if end < start {
+		end = start
+	}
+	first := start - context
+	if first < 1 {
+		first = 1
+	}
+	last := end + context
+	if last > len(lines) {
+		last = len(lines)
+	}
+	if first > last {
+		return nil, first
+	}
+	if first > 1 && ellipsis != "" {
+		excerpt = append(excerpt, Token{Type: Comment, Value: ellipsis + "\n"})
+	}
+	for _, line := range lines[first-1 : last] {
+		for _, token := range line {
+			if token.Value != "" {
+				excerpt = append(excerpt, token)
+			}
+		}
+	}
+	if last < len(lines) && ellipsis != "" {
+		excerpt = append(excerpt, Token{Type: Comment, Value: ellipsis + "\n"})
+	}
+	return excerpt, first
+}
@@ -0,0 +1,41 @@
+package chroma
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestExcerpt(t *testing.T) {
+	tokens := []Token{
+		{Type: Keyword, Value: "one"},
+		{Type: Text, Value: "\ntwo\nthree\n"},
+		{Type: Keyword, Value: "four"},
+		{Type: Text, Value: "\nfive\n"},
+	}
+	excerpt, first := Excerpt(tokens, 3, 3, 1, "…")
+	assert.Equal(t, 2, first)
+	assert.Equal(t, []Token{
+		{Type: Comment, Value: "…\n"},
+		{Type: Text, Value: "two\n"},
+		{Type: Text, Value: "three\n"},
+		{Type: Keyword, Value: "four"},
+		{Type: Text, Value: "\n"},
+		{Type: Comment, Value: "…\n"},
+	}, excerpt)
+
+	excerpt, first = Excerpt(tokens, 4, 5, 2, "")
+	assert.Equal(t, 2, first)
+	text := ""
+	for _, token := range excerpt {
+		text += token.Value
+	}
+	assert.Equal(t, "two\nthree\nfour\nfive\n", text)
+
+	excerpt, first = Excerpt(tokens, 1, 1, 0, "…")
+	assert.Equal(t, 1, first)
+	assert.Equal(t, []Token{{Type: Keyword, Value: "one"}, {Type: Text, Value: "\n"}, {Type: Comment, Value: "…\n"}}, excerpt)
+
+	excerpt, _ = Excerpt(tokens, 10, 12, 1, "…")
+	assert.Equal(t, 0, len(excerpt))
+}
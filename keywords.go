@@ -0,0 +1,102 @@
+package chroma
+
+import (
+	"sort"
+	"strings"
+)
+
+// MustNewKeywordLexer creates a new keyword Lexer or panics.
+func MustNewKeywordLexer(config *Config, categories map[TokenType][]string) *RegexLexer {
+	lexer, err := NewKeywordLexer(config, categories)
+	if err != nil {
+		panic(err)
+	}
+	return lexer
+}
+
+// NewKeywordLexer creates a simple Lexer from lists of keywords.
+//
+// Each key of "categories" is the token type emitted for its words. The keyword rules are
+// combined with generic rules for comments ("#", "//" and "/* */"), quoted strings, numbers,
+// identifiers, operators and punctuation, which is enough for basic highlighting of niche
+// DSLs without writing a regex state machine.
+//
+// Words are matched literally and must not be adjacent to other word characters. Set
+// Config.CaseInsensitive to match them regardless of case.
+func NewKeywordLexer(config *Config, categories map[TokenType][]string) (*RegexLexer, error) {
+	types := make([]TokenType, 0, len(categories))
+	for tokenType := range categories {
+		types = append(types, tokenType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	keywords := make([]Rule, 0, len(types))
+	for _, tokenType := range types {
+		words := make([]string, 0, len(categories[tokenType]))
+		for _, word := range categories[tokenType] {
+			if word != "" {
+				words = append(words, word)
+			}
+		}
+		if len(words) == 0 {
+			continue
+		}
+		keywords = append(keywords, Rule{Words(`(?<!\w)`, `(?!\w)`, words...), tokenType, nil})
+	}
+	return NewLexer(config, func() Rules {
+		return Rules{
+			"root": append(append([]Rule{
+				{`\s+`, Whitespace, nil},
+				{`(#|//).*`, CommentSingle, nil},
+				{`/\*[\s\S]*?\*/`, CommentMultiline, nil},
+			}, keywords...),
+				Rule{`"(\\\\|\\"|[^"])*"`, LiteralStringDouble, nil},
+				Rule{`'(\\\\|\\'|[^'])*'`, LiteralStringSingle, nil},
+				Rule{`0[xX][0-9a-fA-F]+\b`, LiteralNumberHex, nil},
+				Rule{`\d+\.\d*([eE][-+]?\d+)?|\.\d+([eE][-+]?\d+)?|\d+[eE][-+]?\d+`, LiteralNumberFloat, nil},
+				Rule{`\d+`, LiteralNumberInteger, nil},
+				Rule{`[\p{L}_][\p{L}\p{N}_]*`, Name, nil},
+				Rule{`[-+*/%=<>!&|^~?:@$]+`, Operator, nil},
+				Rule{`[()\[\]{},;.]`, Punctuation, nil},
+				Rule{`.`, Text, nil},
+			),
+		}
+	})
+}
+
+// KeywordClassifier returns a post-processor for RegexLexer.AddPostProcessor that reclassifies
+// Name tokens found in categories as the token type of their category, such as Keyword,
+// NameBuiltin or NameException.
+//
+// A single identifier rule and a map lookup are much faster than a rule with a large Words()
+// alternation per category. Words in several categories take the lowest token type.
+func KeywordClassifier(caseInsensitive bool, categories map[TokenType][]string) func(*Token) *Token {
+	types := make([]TokenType, 0, len(categories))
+	for tokenType := range categories {
+		types = append(types, tokenType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	table := map[string]TokenType{}
+	for _, tokenType := range types {
+		for _, word := range categories[tokenType] {
+			if caseInsensitive {
+				word = strings.ToLower(word)
+			}
+			if _, ok := table[word]; !ok {
+				table[word] = tokenType
+			}
+		}
+	}
+	return func(token *Token) *Token {
+		if token.Type != Name {
+			return token
+		}
+		word := token.Value
+		if caseInsensitive {
+			word = strings.ToLower(word)
+		}
+		if tokenType, ok := table[word]; ok {
+			token.Type = tokenType
+		}
+		return token
+	}
+}
@@ -0,0 +1,91 @@
+package chroma
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestKeywordLexer(t *testing.T) {
+	lexer := Coalesce(MustNewKeywordLexer(&Config{Name: "Recipe"}, map[TokenType][]string{
+		Keyword:     {"step", "serve", "@when"},
+		NameBuiltin: {"oven", "stove"},
+	}))
+	it, err := lexer.Tokenise(nil, "step 1: oven 180.5 \"preheat\" # comment\nserve stepper @when")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{
+		{Keyword, "step"}, {Whitespace, " "}, {LiteralNumberInteger, "1"}, {Operator, ":"},
+		{Whitespace, " "}, {NameBuiltin, "oven"}, {Whitespace, " "}, {LiteralNumberFloat, "180.5"},
+		{Whitespace, " "}, {LiteralStringDouble, `"preheat"`}, {Whitespace, " "}, {CommentSingle, "# comment"},
+		{Whitespace, "\n"}, {Keyword, "serve"}, {Whitespace, " "}, {Name, "stepper"},
+		{Whitespace, " "}, {Keyword, "@when"},
+	}, it.Tokens())
+}
+
+func TestKeywordLexerCaseInsensitive(t *testing.T) {
+	lexer := MustNewKeywordLexer(&Config{CaseInsensitive: true}, map[TokenType][]string{
+		Keyword: {"select"},
+	})
+	it, err := lexer.Tokenise(nil, "SELECT Select")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{{Keyword, "SELECT"}, {Whitespace, " "}, {Keyword, "Select"}}, it.Tokens())
+}
+
+func TestKeywordClassifier(t *testing.T) {
+	lexer := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`\s+`, Whitespace, nil},
+			{`"[^"]*"`, LiteralString, nil},
+			{`\w+`, Name, nil},
+		},
+	}).AddPostProcessor(KeywordClassifier(true, map[TokenType][]string{
+		Keyword:       {"If", "raise"},
+		NameBuiltin:   {"len", "raise"},
+		NameException: {"ValueError"},
+	}))
+	it, err := lexer.Tokenise(nil, `if len raise valueerror "if" lengthy`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{
+		{Keyword, "if"}, {Whitespace, " "}, {NameBuiltin, "len"}, {Whitespace, " "},
+		{Keyword, "raise"}, {Whitespace, " "}, {NameException, "valueerror"}, {Whitespace, " "},
+		{LiteralString, `"if"`}, {Whitespace, " "}, {Name, "lengthy"},
+	}, it.Tokens())
+}
+
+func BenchmarkKeywordClassifier(b *testing.B) {
+	keywords := make([]string, 0, 500)
+	for i := 0; i < cap(keywords); i++ {
+		keywords = append(keywords, fmt.Sprintf("keyword%d", i))
+	}
+	source := strings.Repeat("keyword42 identifier keyword499 other_name (x) keyword7\n", 200)
+	common := []Rule{
+		{`\s+`, Whitespace, nil},
+		{`[()]`, Punctuation, nil},
+	}
+	lexers := map[string]Lexer{
+		"Words": MustNewLexer(nil, func() Rules {
+			return Rules{"root": append(common,
+				Rule{Words(`\b`, `\b`, keywords...), Keyword, nil},
+				Rule{`\w+`, Name, nil},
+			)}
+		}),
+		"KeywordClassifier": MustNewLexer(nil, func() Rules {
+			return Rules{"root": append(common, Rule{`\w+`, Name, nil})}
+		}).AddPostProcessor(KeywordClassifier(false, map[TokenType][]string{Keyword: keywords})),
+	}
+	for _, name := range []string{"Words", "KeywordClassifier"} {
+		lexer := lexers[name]
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				it, err := lexer.Tokenise(nil, source)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for token := it(); token != EOF; token = it() {
+				}
+			}
+		})
+	}
+}
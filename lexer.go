@@ -106,6 +106,9 @@ type TokeniseOptions struct {
 	// If true, all EOLs are converted into LF
 	// by replacing CRLF and CR
 	EnsureLF bool
+
+	// If non-nil, records the states and rules that match, including in nested lexers.
+	Coverage *RuleCoverage
 }
 
 // A Lexer for tokenising source code.
@@ -0,0 +1,163 @@
+package lexers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// ANSI lexer for captured terminal output.
+//
+// Escape sequences are stripped, and the text they style is emitted as Generic tokens so that
+// it keeps a similar appearance when re-rendered.
+var ANSI = Register(MustNewLexer(
+	&Config{
+		Name:      "ANSI",
+		Aliases:   []string{"ansi", "ansi-output"},
+		Filenames: []string{"*.ans", "*.ansi"},
+		MimeTypes: []string{"text/x-ansi"},
+	},
+	ansiRules,
+).SetAnalyser(func(text string) float32 {
+	if ansiSGRRe.MatchString(text) {
+		return 0.9
+	}
+	return 0
+}))
+
+var (
+	ansiSGRRe    = regexp.MustCompile(`\x1b\[[0-9;:]*m`)
+	ansiEscapeRe = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)?|[ -/]+[0-~]|[@-Z\\-_])`)
+)
+
+// StripANSI removes ANSI escape sequences from text.
+func StripANSI(text string) string {
+	return ansiEscapeRe.ReplaceAllString(text, "")
+}
+
+func ansiRules() Rules {
+	return Rules{
+		"root": {
+			{`\x1b\[([0-9;:]*)m`, EmitterFunc(ansiSGR), nil},
+			// Other control sequences: cursor movement, erasing, OSC titles and hyperlinks, charsets.
+			{`\x1b\[[0-?]*[ -/]*[@-~]`, Ignore, nil},
+			{`\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)?`, Ignore, nil},
+			{`\x1b(?:[ -/]+[0-~]|[@-Z\\-_])?`, Ignore, nil},
+			{`[^\x1b]+`, EmitterFunc(ansiText), nil},
+		},
+	}
+}
+
+type ansiStateKey struct{}
+
+// ansiAttributes is the SGR state of the terminal.
+type ansiAttributes struct {
+	bold, dim, italic, underline bool
+	// Basic foreground colour 0-7, or -1 for the default colour.
+	foreground int
+	bright     bool
+}
+
+// ansiColours maps basic foreground colours to the Generic token most styles render in a
+// similar colour.
+var ansiColours = map[int]TokenType{
+	1: GenericError,      // red
+	2: GenericInserted,   // green
+	3: GenericHeading,    // yellow
+	4: GenericTraceback,  // blue
+	5: GenericSubheading, // magenta
+	6: GenericPrompt,     // cyan
+}
+
+func (a *ansiAttributes) tokenType() TokenType {
+	if t, ok := ansiColours[a.foreground]; ok {
+		return t
+	}
+	switch {
+	case a.foreground == 0 && a.bright, a.dim:
+		return GenericOutput
+	case a.bold:
+		return GenericStrong
+	case a.italic:
+		return GenericEmph
+	case a.underline:
+		return GenericUnderline
+	}
+	return Text
+}
+
+func ansiState(state *LexerState) *ansiAttributes {
+	attrs, ok := state.Get(ansiStateKey{}).(*ansiAttributes)
+	if !ok {
+		attrs = &ansiAttributes{foreground: -1}
+		state.Set(ansiStateKey{}, attrs)
+	}
+	return attrs
+}
+
+func ansiText(groups []string, state *LexerState) Iterator {
+	return Literator(Token{Type: ansiState(state).tokenType(), Value: groups[0]})
+}
+
+func ansiSGR(groups []string, state *LexerState) Iterator {
+	attrs := ansiState(state)
+	params := strings.FieldsFunc(groups[1], func(r rune) bool { return r == ';' })
+	if len(params) == 0 {
+		params = []string{"0"}
+	}
+	for i := 0; i < len(params); i++ {
+		// Colon separated sub-parameters are only used by extended colours, which are ignored.
+		code, err := strconv.Atoi(strings.SplitN(params[i], ":", 2)[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			*attrs = ansiAttributes{foreground: -1}
+		case code == 1:
+			attrs.bold = true
+		case code == 2:
+			attrs.dim = true
+		case code == 3:
+			attrs.italic = true
+		case code == 4:
+			attrs.underline = true
+		case code == 22:
+			attrs.bold, attrs.dim = false, false
+		case code == 23:
+			attrs.italic = false
+		case code == 24:
+			attrs.underline = false
+		case code >= 30 && code <= 37:
+			attrs.foreground, attrs.bright = code-30, false
+		case code >= 90 && code <= 97:
+			attrs.foreground, attrs.bright = code-90, true
+		case code == 39:
+			attrs.foreground = -1
+		case code == 38 || code == 48:
+			// Extended colours: 38;5;n or 38;2;r;g;b. Only the 16 basic colours are mapped.
+			if strings.Contains(params[i], ":") || i+1 >= len(params) {
+				break
+			}
+			switch params[i+1] {
+			case "5":
+				if i+2 < len(params) && code == 38 {
+					if n, err := strconv.Atoi(params[i+2]); err == nil && n < 16 {
+						attrs.foreground, attrs.bright = n%8, n >= 8
+					} else {
+						attrs.foreground = -1
+					}
+				}
+				i += 2
+			case "2":
+				if code == 38 {
+					attrs.foreground = -1
+				}
+				i += 4
+			}
+		}
+	}
+	return Literator(Token{Type: Ignore, Value: groups[0]})
+}
@@ -0,0 +1,12 @@
+package lexers
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestStripANSI(t *testing.T) {
+	input := "\x1b[1;31mFAIL\x1b[0m \x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\\x1b[2K\x1b(B done"
+	assert.Equal(t, "FAIL link done", StripANSI(input))
+}
@@ -0,0 +1,45 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// ARM lexer is JSON with Azure Resource Manager template expressions
+// ("[concat(parameters('name'), '-vm')]") highlighted inside strings.
+var ARM = Register(MustNewLexer(
+	&Config{
+		Name:         "ARM Template",
+		Aliases:      []string{"arm", "armtemplate"},
+		Filenames:    []string{"azuredeploy.json", "azuredeploy.parameters.json", "mainTemplate.json", "*.arm.json"},
+		MimeTypes:    []string{"application/vnd.microsoft.arm+json"},
+		DotAll:       true,
+		NotMultiline: true,
+		// Higher than JSON, which claims all *.json files.
+		Priority: 2,
+	},
+	armRules,
+))
+
+func armRules() Rules {
+	json := Get("JSON").(*RegexLexer).MustRules()
+	return json.Merge(Rules{
+		"simplevalue": append([]Rule{
+			// "[[" escapes a literal leading bracket.
+			{`(")(\[)(?!\[)(?=(?:\\.|[^"\\])*\]")`, ByGroups(LiteralStringDouble, Punctuation), Push("expression")},
+		}, json["simplevalue"]...),
+		"expression": {
+			{`(\])(")`, ByGroups(Punctuation, LiteralStringDouble), Pop(1)},
+			{`\s+`, Text, nil},
+			{`'(?:''|[^'])*'`, LiteralStringSingle, nil},
+			{`\\.`, LiteralStringEscape, nil},
+			{`(true|false|null)\b`, KeywordConstant, nil},
+			{`-?\d+\.\d+`, LiteralNumberFloat, nil},
+			{`-?\d+`, LiteralNumberInteger, nil},
+			{`(\.)([a-zA-Z_]\w*)`, ByGroups(Punctuation, NameAttribute), nil},
+			{`([a-zA-Z_]\w*)(\.)([a-zA-Z_]\w*)(?=\s*\()`, ByGroups(NameNamespace, Punctuation, NameFunction), nil},
+			{`[a-zA-Z_]\w*(?=\s*\()`, NameFunction, nil},
+			{`[a-zA-Z_]\w*`, Name, nil},
+			{`[()\[\],]`, Punctuation, nil},
+		},
+	})
+}
@@ -0,0 +1,144 @@
+package lexers
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// Classifier is a trainable naive Bayes classifier that identifies the language of text, for
+// text that has no filename to match, and whose lexers have no analyser that recognises it.
+//
+// Text is scored by how likely its words and symbols are in each trained language, and by how
+// likely the token statistics produced by that language's lexer are: lexers that do not
+// understand text tend to produce unusual token types, such as Error.
+//
+// A Classifier is not safe for concurrent training.
+type Classifier struct {
+	classes    map[string]*classifierClass
+	vocabulary map[string]bool
+}
+
+type classifierClass struct {
+	lexer    chroma.Lexer
+	features map[string]int
+	total    int
+}
+
+// NewClassifier creates a new, untrained, Classifier.
+func NewClassifier() *Classifier {
+	return &Classifier{classes: map[string]*classifierClass{}, vocabulary: map[string]bool{}}
+}
+
+// Train the classifier with a sample of text in the language of lexer.
+func (c *Classifier) Train(lexer chroma.Lexer, text string) error {
+	features, err := classifierFeatures(lexer, text)
+	if err != nil {
+		return err
+	}
+	name := lexer.Config().Name
+	class, ok := c.classes[name]
+	if !ok {
+		class = &classifierClass{lexer: lexer, features: map[string]int{}}
+		c.classes[name] = class
+	}
+	for feature, n := range features {
+		class.features[feature] += n
+		class.total += n
+		c.vocabulary[feature] = true
+	}
+	return nil
+}
+
+// ClassifierScore is the score of a language for some text.
+type ClassifierScore struct {
+	Lexer chroma.Lexer
+	// Log-likelihood of the features of the text. Higher is more likely.
+	Score float64
+}
+
+// Scores returns the score of each trained language for text, most likely first.
+func (c *Classifier) Scores(text string) []ClassifierScore {
+	scores := make([]ClassifierScore, 0, len(c.classes))
+	vocabulary := float64(len(c.vocabulary))
+	for _, class := range c.classes {
+		features, err := classifierFeatures(class.lexer, text)
+		if err != nil {
+			continue
+		}
+		words, tokens := 0.0, 0.0
+		wordScore, tokenScore := 0.0, 0.0
+		for feature, count := range features {
+			// Additive smoothing, with one extra entry for unseen features.
+			p := (float64(class.features[feature]) + classifierSmoothing) /
+				(float64(class.total) + classifierSmoothing*(vocabulary+1))
+			if isTokenTypeFeature(feature) {
+				tokens += float64(count)
+				tokenScore += float64(count) * math.Log(p)
+			} else {
+				words += float64(count)
+				wordScore += float64(count) * math.Log(p)
+			}
+		}
+		// Each lexer produces a different number of tokens for the same text, so token types
+		// are weighted as if there were one per word.
+		score := wordScore
+		if tokens > 0 {
+			score += tokenScore / tokens * words
+		}
+		scores = append(scores, ClassifierScore{Lexer: class.lexer, Score: score})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Lexer.Config().Name < scores[j].Lexer.Config().Name
+	})
+	return scores
+}
+
+// Analyse returns the most likely lexer for text, or nil if the classifier is untrained.
+//
+// It is an alternative to Analyse, which relies on lexers' hand written analysers, and can be
+// used as one with AnalyseWith.
+func (c *Classifier) Analyse(text string) chroma.Lexer {
+	scores := c.Scores(text)
+	if len(scores) == 0 {
+		return nil
+	}
+	return scores[0].Lexer
+}
+
+// classifierSmoothing is added to the count of every feature, so that features not seen in
+// training do not rule a language out.
+const classifierSmoothing = 0.1
+
+var classifierWordRe = regexp.MustCompile(`[\pL_][\pL\pN_]*|[^\s\pL\pN_]+`)
+
+// classifierFeatures counts the features of text: its words and runs of symbols, and the types
+// of the tokens produced by lexer.
+func classifierFeatures(lexer chroma.Lexer, text string) (map[string]int, error) {
+	it, err := lexer.Tokenise(nil, text)
+	if err != nil {
+		return nil, err
+	}
+	stats := chroma.NewTokenStatistics(it)
+	features := make(map[string]int, len(stats.Counts))
+	for tokenType, n := range stats.Counts {
+		features[tokenTypeFeaturePrefix+tokenType.String()] += n
+	}
+	for _, word := range classifierWordRe.FindAllString(text, -1) {
+		features[word]++
+	}
+	return features, nil
+}
+
+// tokenTypeFeaturePrefix distinguishes token type features from words.
+const tokenTypeFeaturePrefix = "\x00"
+
+func isTokenTypeFeature(feature string) bool {
+	return strings.HasPrefix(feature, tokenTypeFeaturePrefix)
+}
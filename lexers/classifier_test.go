@@ -0,0 +1,99 @@
+package lexers
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+var classifierSamples = map[string]string{
+	"python": `import os
+
+class Reader(object):
+    def __init__(self, path):
+        self.path = path
+
+    def read(self):
+        with open(self.path) as f:
+            return [line.strip() for line in f if line]
+`,
+	"go": `package main
+
+import "fmt"
+
+type Reader struct {
+	path string
+}
+
+func (r *Reader) Read() ([]string, error) {
+	if r.path == "" {
+		return nil, fmt.Errorf("no path")
+	}
+	return []string{r.path}, nil
+}
+`,
+	"ruby": `require 'set'
+
+class Reader
+  attr_reader :path
+
+  def initialize(path)
+    @path = path
+  end
+
+  def read
+    File.readlines(@path).map(&:strip).reject(&:empty?)
+  end
+end
+`,
+	"javascript": `const fs = require("fs");
+
+class Reader {
+  constructor(path) {
+    this.path = path;
+  }
+
+  read() {
+    return fs.readFileSync(this.path, "utf8").split("\n").filter((line) => line !== "");
+  }
+}
+`,
+}
+
+func TestClassifier(t *testing.T) {
+	classifier := NewClassifier()
+	assert.True(t, classifier.Analyse("anything") == nil)
+	for alias, sample := range classifierSamples {
+		assert.NoError(t, classifier.Train(Get(alias), sample))
+	}
+	tests := map[string]string{
+		"python":     "def main():\n    for name in os.listdir('.'):\n        print(name)\n",
+		"go":         "func main() {\n\tfor _, name := range names {\n\t\tfmt.Println(name)\n\t}\n}\n",
+		"ruby":       "def main\n  Dir.entries('.').each do |name|\n    puts name\n  end\nend\n",
+		"javascript": "function main() {\n  for (const name of fs.readdirSync(\".\")) {\n    console.log(name);\n  }\n}\n",
+	}
+	for alias, text := range tests {
+		t.Run(alias, func(t *testing.T) {
+			scores := classifier.Scores(text)
+			assert.Equal(t, 4, len(scores))
+			names := []string{}
+			for _, score := range scores {
+				names = append(names, score.Lexer.Config().Name)
+			}
+			assert.Equal(t, Get(alias).Config().Name, classifier.Analyse(text).Config().Name, "%v", names)
+			assert.Equal(t, Get(alias), AnalyseWith(classifier, text))
+		})
+	}
+}
+
+func TestAnalyseWith(t *testing.T) {
+	classifier := NewClassifier()
+	assert.Zero(t, AnalyseWith(classifier, "anything"))
+
+	// Lexers that are not registered are not picked.
+	unregistered := chroma.MustNewLexer(&chroma.Config{Name: "Unregistered"}, PlaintextRules)
+	assert.NoError(t, classifier.Train(unregistered, "anything"))
+	assert.Zero(t, AnalyseWith(classifier, "anything"))
+}
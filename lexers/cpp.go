@@ -0,0 +1,89 @@
+package lexers
+
+import (
+	"regexp"
+	"strings"
+
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+var cppIncludeRe = regexp.MustCompile(`#include <[a-z_]+>`)
+
+// CPP lexer, which extends the C lexer rules with C++ specific syntax.
+var CPP = Register(MustNewLexer(
+	&Config{
+		Name:      "C++",
+		Aliases:   []string{"cpp", "c++"},
+		Filenames: []string{"*.cpp", "*.hpp", "*.c++", "*.h++", "*.cc", "*.hh", "*.cxx", "*.hxx", "*.C", "*.H", "*.cp", "*.CPP", "*.tpp"},
+		MimeTypes: []string{"text/x-c++hdr", "text/x-c++src"},
+		EnsureNL:  true,
+	},
+	cppRules,
+).SetAnalyser(func(text string) float32 {
+	if cppIncludeRe.MatchString(text) {
+		return 0.2
+	}
+	if strings.Contains(text, "using namespace ") {
+		return 0.4
+	}
+	return 0
+}))
+
+// User-defined literal suffixes: user suffixes must start with an
+// underscore, the rest are provided by the standard library.
+const cppUDLSuffix = `(_\w+|(?:min|ms|us|ns|il|if|sv|h|s|i|y|d)\b)`
+
+// cppLiteral returns rules matching a literal with a user-defined literal
+// suffix, then without one, so that literals without a suffix do not emit an
+// empty affix token.
+func cppLiteral(pattern string, mutator Mutator, emitters ...Emitter) []Rule {
+	return []Rule{
+		{pattern + cppUDLSuffix, ByGroups(append(emitters, LiteralStringAffix)...), mutator},
+		{pattern, ByGroups(emitters...), mutator},
+	}
+}
+
+func cppRules() Rules {
+	c := Get("C").(*RegexLexer).MustRules()
+	statements := []Rule{
+		{Words(``, `\b`, `reinterpret_cast`, `static_assert`, `thread_local`, `dynamic_cast`, `static_cast`, `const_cast`, `co_return`, `protected`, `namespace`, `consteval`, `constexpr`, `constinit`, `typename`, `co_await`, `co_yield`, `operator`, `restrict`, `explicit`, `template`, `override`, `noexcept`, `requires`, `decltype`, `alignof`, `private`, `alignas`, `virtual`, `mutable`, `nullptr`, `concept`, `export`, `friend`, `typeid`, `throws`, `public`, `delete`, `final`, `throw`, `catch`, `using`, `this`, `new`, `try`), Keyword, nil},
+		{`(enum)\b(\s+)(class)\b(\s*)`, ByGroups(Keyword, Text, Keyword, Text), Push("classname")},
+		{`(class|struct|enum|union)\b(\s*)`, ByGroups(Keyword, Text), Push("classname")},
+		{`\[\[.+\]\]`, NameAttribute, nil},
+	}
+	statements = append(statements, cppLiteral(`((?:u8|u|U|L)?R)(")([^\\()\s]{0,16})(\()((?:.|\n)*?)(\)\3)(")`, nil,
+		LiteralStringAffix, LiteralString, LiteralStringDelimiter, LiteralStringDelimiter, LiteralString, LiteralStringDelimiter, LiteralString)...)
+	statements = append(statements, Rule{`(u8|u|U)(")`, ByGroups(LiteralStringAffix, LiteralString), Push("string")})
+	for _, number := range []struct {
+		pattern   string
+		tokenType TokenType
+	}{
+		{`((?:\d+\.\d*|\.\d+|\d+)[eE][+-]?\d+[LlUu]*)`, LiteralNumberFloat},
+		{`((?:\d+\.\d*|\.\d+|\d+[fF])[fF]?)`, LiteralNumberFloat},
+		{`(0[xX](?:[0-9A-Fa-f](?:'?[0-9A-Fa-f]+)*)[LlUu]*)`, LiteralNumberHex},
+		{`(0(?:'?[0-7]+)+[LlUu]*)`, LiteralNumberOct},
+		{`(0[Bb][01](?:'?[01]+)*[LlUu]*)`, LiteralNumberBin},
+		{`([0-9](?:'?[0-9]+)*[LlUu]*)`, LiteralNumberInteger},
+	} {
+		statements = append(statements, cppLiteral(number.pattern, nil, number.tokenType)...)
+	}
+	statements = append(statements,
+		Rule{`__(multiple_inheritance|virtual_inheritance|single_inheritance|interface|uuidof|super|event)\b`, KeywordReserved, nil},
+		Rule{`__(offload|blockingoffload|outer)\b`, KeywordPseudo, nil},
+	)
+	return c.Merge(Rules{
+		"classname": {
+			{`(\[\[.+\]\])(\s*)`, ByGroups(NameAttribute, Text), nil},
+			{`[a-zA-Z_]\w*`, NameClass, Pop(1)},
+			{`\s*(?=[>{])`, Text, Pop(1)},
+		},
+		"statements": append(statements, c["statements"]...),
+		"statement": {
+			Include("whitespace"),
+			{`[{]|<%`, Punctuation, Push("root")},
+			{`[;}]|%>`, Punctuation, Pop(1)},
+			Include("statements"),
+		},
+		"string": append(cppLiteral(`(")`, Pop(1), LiteralString), c["string"]...),
+	})
+}
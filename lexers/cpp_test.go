@@ -0,0 +1,37 @@
+package lexers
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestCppLiteralsWithoutSuffix(t *testing.T) {
+	it, err := Get("cpp").Tokenise(nil, `int a<:2:> = {0x1F, 1.5, 1e3, 7_km, "x"s};`)
+	assert.NoError(t, err)
+	assert.Equal(t, []chroma.Token{
+		{chroma.KeywordType, "int"}, {chroma.Text, " "}, {chroma.Name, "a"},
+		{chroma.Punctuation, "<:"}, {chroma.LiteralNumberInteger, "2"}, {chroma.Punctuation, ":>"},
+		{chroma.Text, " "}, {chroma.Operator, "="}, {chroma.Text, " "}, {chroma.Punctuation, "{"},
+		{chroma.LiteralNumberHex, "0x1F"}, {chroma.Punctuation, ","}, {chroma.Text, " "},
+		{chroma.LiteralNumberFloat, "1.5"}, {chroma.Punctuation, ","}, {chroma.Text, " "},
+		{chroma.LiteralNumberFloat, "1e3"}, {chroma.Punctuation, ","}, {chroma.Text, " "},
+		{chroma.LiteralNumberInteger, "7"}, {chroma.LiteralStringAffix, "_km"}, {chroma.Punctuation, ","}, {chroma.Text, " "},
+		{chroma.LiteralString, `"`}, {chroma.LiteralString, "x"},
+		{chroma.LiteralString, `"`}, {chroma.LiteralStringAffix, "s"},
+		{chroma.Punctuation, "}"}, {chroma.Punctuation, ";"},
+	}, it.Tokens())
+}
+
+func TestCStringPrefix(t *testing.T) {
+	it, err := Get("c").Tokenise(nil, `L"x" 'y' L'z'`)
+	assert.NoError(t, err)
+	assert.Equal(t, []chroma.Token{
+		{chroma.LiteralStringAffix, "L"}, {chroma.LiteralString, `"`}, {chroma.LiteralString, "x"},
+		{chroma.LiteralString, `"`}, {chroma.Text, " "},
+		{chroma.LiteralStringChar, "'y'"}, {chroma.Text, " "},
+		{chroma.LiteralStringAffix, "L"}, {chroma.LiteralStringChar, "'"}, {chroma.LiteralStringChar, "z"},
+		{chroma.LiteralStringChar, "'"},
+	}, it.Tokens())
+}
@@ -0,0 +1,64 @@
+package lexers
+
+import (
+	"fmt"
+	"regexp"
+
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// CSV lexer.
+var CSV = Register(MustNewLexer(
+	&Config{
+		Name:      "CSV",
+		Aliases:   []string{"csv"},
+		Filenames: []string{"*.csv"},
+		MimeTypes: []string{"text/csv"},
+	},
+	func() Rules { return delimitedRules(",") },
+))
+
+// TSV lexer.
+var TSV = Register(MustNewLexer(
+	&Config{
+		Name:      "TSV",
+		Aliases:   []string{"tsv"},
+		Filenames: []string{"*.tsv", "*.tab"},
+		MimeTypes: []string{"text/tab-separated-values"},
+	},
+	func() Rules { return delimitedRules("\t") },
+))
+
+// Successive columns cycle through these token types so that adjacent
+// columns are rendered in different colours.
+var delimitedColumnTypes = []TokenType{
+	NameVariable,
+	Keyword,
+	LiteralString,
+	NameFunction,
+	LiteralNumber,
+	NameClass,
+}
+
+// delimitedRules returns rules for a file of sep-separated records. The first
+// record is treated as a header row.
+func delimitedRules(sep string) Rules {
+	sep = regexp.QuoteMeta(sep)
+	rules := Rules{
+		"root": delimitedFieldRules(sep, GenericHeading, nil, Push("column0")),
+	}
+	for i := range delimitedColumnTypes {
+		next := fmt.Sprintf("column%d", (i+1)%len(delimitedColumnTypes))
+		rules[fmt.Sprintf("column%d", i)] = delimitedFieldRules(sep, delimitedColumnTypes[i], Push("#pop", next), Push("#pop", "column0"))
+	}
+	return rules
+}
+
+func delimitedFieldRules(sep string, field TokenType, onSep, onNewline Mutator) []Rule {
+	return []Rule{
+		{`(")((?:[^"]|"")*)(")`, ByGroups(LiteralStringDelimiter, field, LiteralStringDelimiter), nil},
+		{sep, Punctuation, onSep},
+		{`\r?\n`, Text, onNewline},
+		{`[^"\r\n` + sep + `]+|"`, field, nil},
+	}
+}
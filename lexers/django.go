@@ -0,0 +1,21 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// HTMLDjango lexer is Django/Jinja templates embedded in HTML.
+var HTMLDjango = Register(DelegatingLexer(HTML, MustNewXMLLexer(
+	embedded,
+	"embedded/django_jinja.xml",
+).SetConfig(
+	&Config{
+		Name:      "HTML+Django/Jinja",
+		Aliases:   []string{"html+django", "html+jinja", "htmldjango"},
+		Filenames: []string{"*.html.j2", "*.htm.j2", "*.html.jinja", "*.html.jinja2", "*.djhtml"},
+		MimeTypes: []string{"text/html+django", "text/html+jinja"},
+		DotAll:    true,
+		// Higher than Django/Jinja, which claims all *.j2 files.
+		Priority: 2,
+	},
+)))
@@ -65,8 +65,11 @@ var GoHTMLTemplate = Register(DelegatingLexer(HTML, MustNewXMLLexer(
 	"embedded/go_template.xml",
 ).SetConfig(
 	&Config{
-		Name:    "Go HTML Template",
-		Aliases: []string{"go-html-template"},
+		Name:      "Go HTML Template",
+		Aliases:   []string{"go-html-template"},
+		Filenames: []string{"*.gohtml", "*.html.tmpl"},
+		// Higher than Cheetah, which claims all *.tmpl files.
+		Priority: 2,
 	},
 )))
 
@@ -75,7 +78,9 @@ var GoTextTemplate = Register(MustNewXMLLexer(
 	"embedded/go_template.xml",
 ).SetConfig(
 	&Config{
-		Name:    "Go Text Template",
-		Aliases: []string{"go-text-template"},
+		Name:      "Go Text Template",
+		Aliases:   []string{"go-text-template"},
+		Filenames: []string{"*.gotmpl", "*.go.tmpl", "*.txt.tmpl"},
+		Priority:  2,
 	},
 ))
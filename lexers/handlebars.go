@@ -0,0 +1,18 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// HTMLHandlebars lexer is Handlebars templates embedded in HTML.
+var HTMLHandlebars = Register(DelegatingLexer(HTML, MustNewXMLLexer(
+	embedded,
+	"embedded/handlebars.xml",
+).SetConfig(
+	&Config{
+		Name:      "HTML+Handlebars",
+		Aliases:   []string{"html+handlebars"},
+		Filenames: []string{"*.handlebars", "*.hbs"},
+		MimeTypes: []string{"text/html+handlebars", "text/x-handlebars-template"},
+	},
+)))
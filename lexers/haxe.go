@@ -307,7 +307,7 @@ func haxeRules() Rules {
 		"expr-chain": {
 			Include("spaces"),
 			{`(?:\+\+|\-\-)`, Operator, nil},
-			{`(?:%=|&=|\|=|\^=|\+=|\-=|\*=|/=|<<=|>\s*>\s*=|>\s*>\s*>\s*=|==|!=|<=|>\s*=|&&|\|\||<<|>>>|>\s*>|\.\.\.|<|>|%|&|\||\^|\+|\*|/|\-|=>|=)`, Operator, Push("#pop", "expr")},
+			{`(?:->|%=|&=|\|=|\^=|\+=|\-=|\*=|/=|<<=|>\s*>\s*=|>\s*>\s*>\s*=|==|!=|<=|>\s*=|&&|\|\||<<|>>>|>\s*>|\.\.\.|<|>|%|&|\||\^|\+|\*|/|\-|=>|=)`, Operator, Push("#pop", "expr")},
 			{`(?:in)\b`, Keyword, Push("#pop", "expr")},
 			{`\?`, Operator, Push("#pop", "expr", "ternary", "expr")},
 			{`(\.)((?!(?:function|class|static|var|if|else|while|do|for|break|return|continue|extends|implements|import|switch|case|default|public|private|try|untyped|catch|new|this|throw|extern|enum|in|interface|cast|override|dynamic|typedef|package|inline|using|null|true|false|abstract)\b)(?:_*[a-z]\w*|_+[0-9]\w*|_*[A-Z]\w*|_+|\$\w+))`, ByGroups(Punctuation, Name), nil},
@@ -465,7 +465,13 @@ func haxeRules() Rules {
 		},
 		"type-parenthesis": {
 			Include("spaces"),
-			Default(Pop(1), Push("parenthesis-close"), Push("type")),
+			{`\)`, Punctuation, Pop(1)},
+			Default(Pop(1), Push("type-parenthesis-sep"), Push("type")),
+		},
+		"type-parenthesis-sep": {
+			Include("spaces"),
+			{`\)`, Punctuation, Pop(1)},
+			{`,`, Punctuation, Push("type")},
 		},
 		"type-check": {
 			Include("spaces"),
@@ -545,6 +551,7 @@ func haxeRules() Rules {
 		"parenthesis-close": {
 			Include("spaces"),
 			{`\)`, Punctuation, Pop(1)},
+			{`,`, Punctuation, Push("flag", "expr")},
 		},
 		"var": {
 			Include("spaces"),
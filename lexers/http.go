@@ -11,8 +11,8 @@ var HTTP = Register(httpBodyContentTypeLexer(MustNewLexer(
 	&Config{
 		Name:         "HTTP",
 		Aliases:      []string{"http"},
-		Filenames:    []string{},
-		MimeTypes:    []string{},
+		Filenames:    []string{"*.http"},
+		MimeTypes:    []string{"message/http"},
 		NotMultiline: true,
 		DotAll:       true,
 	},
@@ -23,6 +23,7 @@ func httpRules() Rules {
 	return Rules{
 		"root": {
 			{`(GET|POST|PUT|DELETE|HEAD|OPTIONS|TRACE|PATCH|CONNECT)( +)([^ ]+)( +)(HTTP)(/)([123](?:\.[01])?)(\r?\n|\Z)`, ByGroups(NameFunction, Text, NameNamespace, Text, KeywordReserved, Operator, LiteralNumber, Text), Push("headers")},
+			{`(GET|POST|PUT|DELETE|HEAD|OPTIONS|TRACE|PATCH|CONNECT)( +)([^ \r\n]+)(\r?\n|\Z)`, ByGroups(NameFunction, Text, NameNamespace, Text), Push("headers")},
 			{`(HTTP)(/)([123](?:\.[01])?)( +)(\d{3})( *)([^\r\n]*)(\r?\n|\Z)`, ByGroups(KeywordReserved, Operator, LiteralNumber, Text, LiteralNumber, Text, NameException, Text), Push("headers")},
 		},
 		"headers": {
@@ -102,7 +103,11 @@ func (d *httpBodyContentTyper) Tokenise(options *TokeniseOptions, text string) (
 					contentType = strings.TrimSpace(contentType[:pos])
 				}
 			}
-		case token.Type == Generic && contentType != "":
+		case token.Type == Generic && contentType == "":
+			{
+				token.Type = Text
+			}
+		case token.Type == Generic:
 			{
 				lexer := MatchMimeType(contentType)
 
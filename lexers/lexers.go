@@ -50,6 +50,20 @@ func Match(filename string) chroma.Lexer {
 	return GlobalLexerRegistry.Match(filename)
 }
 
+// MatchContent returns the lexer matching filename, using the analysers of the candidates
+// to choose between lexers that share a file extension.
+func MatchContent(filename, text string) chroma.Lexer {
+	return GlobalLexerRegistry.MatchContent(filename, text)
+}
+
+// OverrideFilename maps filenames matching glob to lexer in the global registry, taking
+// precedence over the filename patterns of the built-in lexers.
+//
+//	err := lexers.OverrideFilename("*.conf", lexers.Get("nginx"))
+func OverrideFilename(glob string, lexer chroma.Lexer) error {
+	return GlobalLexerRegistry.OverrideFilename(glob, lexer)
+}
+
 // Register a Lexer with the global registry.
 func Register(lexer chroma.Lexer) chroma.Lexer {
 	return GlobalLexerRegistry.Register(lexer)
@@ -60,6 +74,18 @@ func Analyse(text string) chroma.Lexer {
 	return GlobalLexerRegistry.Analyse(text)
 }
 
+// AnalyseWith picks the lexer for text with classifier, such as a trained Classifier, rather
+// than with the analysers of the lexers.
+func AnalyseWith(classifier chroma.TextClassifier, text string) chroma.Lexer {
+	return GlobalLexerRegistry.AnalyseWith(classifier, text)
+}
+
+// AnalyseAll returns the lexers whose analysers score text above zero and at least min, highest
+// score first.
+func AnalyseAll(text string, min float32) []chroma.ScoredLexer {
+	return GlobalLexerRegistry.AnalyseAll(text, min)
+}
+
 // PlaintextRules is used for the fallback lexer as well as the explicit
 // plaintext lexer.
 func PlaintextRules() chroma.Rules {
@@ -44,6 +44,123 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"Dockerfile", "Docker"},
+		{"Dockerfile.prod", "Docker"},
+		{"Dockerfile-dev", "Docker"},
+		{"api.Dockerfile", "Docker"},
+		{"Containerfile", "Docker"},
+		{"Makefile", "Makefile"},
+		{"GNUmakefile", "Makefile"},
+		{"rules.mk", "Makefile"},
+		{".gitconfig", "Git Config"},
+		{".gitmodules", "Git Config"},
+		{"nginx.service", "SYSTEMD"},
+		{"setup.cfg", "INI"},
+		{"main.tf", "Terraform"},
+		{"prod.tfvars", "Terraform"},
+		{"job.hcl", "HCL"},
+		{"add.wat", "WebAssembly"},
+		{"spec.wast", "WebAssembly"},
+		{"requests.http", "HTTP"},
+		{"app.log", "Log"},
+		{"nginx-site.conf", "Nginx configuration file"},
+		{"default.nginx", "Nginx configuration file"},
+		{"httpd.conf", "ApacheConf"},
+		{"users.avdl", "Avro IDL"},
+		{"BUILD", "Starlark"},
+		{"defs.bzl", "Starlark"},
+		{"app.libsonnet", "Jsonnet"},
+		{"payroll.cbl", "COBOL"},
+		{"account.e", "Eiffel"},
+		{"layout.gohtml", "Go HTML Template"},
+		{"config.go.tmpl", "Go Text Template"},
+		{"page.html.j2", "HTML+Django/Jinja"},
+		{"nginx.conf.j2", "Django/Jinja"},
+		{"card.hbs", "HTML+Handlebars"},
+		{"list.mustache", "Mustache"},
+		{"users.csv", "CSV"},
+		{"genes.tsv", "TSV"},
+		{"date.regex", "Regex"},
+		{"emp_pkg.pkb", "PL/SQL"},
+		{"Basic.lean", "Lean"},
+		{"site.yml.j2", "YAML+Jinja"},
+		{"top.sls", "YAML+Jinja"},
+		{"Berksfile", "Ruby"},
+		{"cpu.flux", "Flux"},
+		{"cpu.influxql", "InfluxQL"},
+		{"signins.kql", "Kusto"},
+		{"errors.spl", "Splunk SPL"},
+		{"azuredeploy.json", "ARM Template"},
+		{"network.arm.json", "ARM Template"},
+		{"package.json", "JSON"},
+		{"Hello.javap", "Javap"},
+		{"openapi.yaml", "OpenAPI YAML"},
+		{"swagger.json", "OpenAPI JSON"},
+		{"values.yaml", "YAML"},
+		{"Main.purs", "PureScript"},
+		{"reply.eml", "E-mail"},
+		{"deps.dot", "Graphviz"},
+		{"pipeline.gv", "Graphviz"},
+		{"sequence.puml", "PlantUML"},
+		{"flow.mmd", "Mermaid"},
+		{".env", "Dotenv"},
+		{"prod.env", "Dotenv"},
+		{"firmware.xxd", "Hexdump"},
+		{"Expr.g4", "ANTLR"},
+		{"parse.y", "Bison"},
+		{"scan.l", "Lex"},
+		{"lighting.glsl", "GLSL"},
+		{"cull.comp", "GLSL"},
+		{"sketch.pde", "Processing"},
+		{"macros.vba", "VBA"},
+		{"UserForm1.frm", "VBA"},
+		{"build.ansi", "ANSI"},
+	}
+	for _, test := range tests {
+		t.Run(test.filename, func(t *testing.T) {
+			lexer := lexers.Match(test.filename)
+			assert.NotZero(t, lexer)
+			assert.Equal(t, test.expected, lexer.Config().Name)
+		})
+	}
+}
+
+func TestMatchContent(t *testing.T) {
+	tests := []struct {
+		filename string
+		source   string
+		expected string
+	}{
+		{"foo.h", "#include <stdio.h>\nint main(void);\n", "C"},
+		{"foo.h", "#import <Foundation/Foundation.h>\n@interface Foo : NSObject\n@end\n", "Objective-C"},
+		{"foo.m", "function y = f(x)\n  y = x + 1;\nend\n", "Matlab"},
+		{"foo.m", "function y = f(x)\n  y = x + 1;\nendfunction\n", "Octave"},
+		{"foo.m", "f[x_, n_Integer] := x^n\n", "Mathematica"},
+		{"foo.m", "<%init>\nmy $x = 1;\n</%init>\n", "Mason"},
+		{"foo.pl", "use strict;\nmy $x = 1;\n", "Perl"},
+		{"foo.pl", "use v6;\nmy $x = 1;\n", "Raku"},
+		{"foo.pl", "ancestor(X, Y) :- parent(X, Y).\n", "Prolog"},
+		{"foo.sql", "SELECT `a` FROM `b`;\n", "MySQL"},
+		{"foo.v", "module counter(input clk, output reg [3:0] q);\nendmodule\n", "verilog"},
+		{"foo.v", "module main\n\nfn main() {\n\tprintln('hi')\n}\n", "V"},
+		{"foo.v", "Theorem t : True.\nProof.\nauto.\nQed.\n", "Coq"},
+		{"foo.go", "package main\n", "Go"},
+	}
+	for _, test := range tests {
+		t.Run(test.filename+"/"+test.expected, func(t *testing.T) {
+			lexer := lexers.MatchContent(test.filename, test.source)
+			assert.NotZero(t, lexer)
+			assert.Equal(t, test.expected, lexer.Config().Name)
+		})
+	}
+	assert.Zero(t, lexers.MatchContent("foo.nosuchextension", "text"))
+}
+
 func TestGlobs(t *testing.T) {
 	filename := "main.go"
 	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
@@ -65,13 +182,17 @@ func BenchmarkGet(b *testing.B) {
 	}
 }
 
+// ruleCoverage records the rules matched by TestLexers when RULE_COVERAGE is set.
+var ruleCoverage *chroma.RuleCoverage
+
 func FileTest(t *testing.T, lexer chroma.Lexer, sourceFile, expectedFilename string) {
 	t.Helper()
 	t.Run(lexer.Config().Name+"/"+sourceFile, func(t *testing.T) {
 		// Read and tokenise source text.
 		sourceBytes, err := os.ReadFile(sourceFile)
 		assert.NoError(t, err)
-		actualTokens, err := chroma.Tokenise(lexer, nil, string(sourceBytes))
+		options := &chroma.TokeniseOptions{State: "root", EnsureLF: true, Coverage: ruleCoverage}
+		actualTokens, err := chroma.Tokenise(lexer, options, string(sourceBytes))
 		assert.NoError(t, err)
 
 		// Check for error tokens early
@@ -106,10 +227,23 @@ func FileTest(t *testing.T, lexer chroma.Lexer, sourceFile, expectedFilename str
 }
 
 // Test source files are in the form <key>.<key> and validation data is in the form <key>.<key>.expected.
+//
+// If RULE_COVERAGE is set to a filename, a report of the lexer rules that never matched is written to it.
 func TestLexers(t *testing.T) {
 	files, err := os.ReadDir("testdata")
 	assert.NoError(t, err)
 
+	if filename := os.Getenv("RULE_COVERAGE"); filename != "" {
+		ruleCoverage = chroma.NewRuleCoverage()
+		defer func() {
+			f, err := os.Create(filename)
+			assert.NoError(t, err)
+			assert.NoError(t, ruleCoverage.WriteReport(f))
+			assert.NoError(t, f.Close())
+			ruleCoverage = nil
+		}()
+	}
+
 	for _, file := range files {
 		// skip text analysis test files
 		if file.Name() == "analysis" {
@@ -0,0 +1,87 @@
+package lexers
+
+import (
+	"regexp"
+
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+var (
+	openAPIYAMLRe = regexp.MustCompile(`(?m)^(?:openapi:\s*["']?3\.|swagger:\s*["']?2\.)`)
+	openAPIJSONRe = regexp.MustCompile(`"(?:openapi"\s*:\s*"3\.|swagger"\s*:\s*"2\.)`)
+)
+
+// HTTP methods that may appear as keys of an OpenAPI path item.
+const openAPIMethods = `(get|put|post|delete|options|head|patch|trace)`
+
+// OpenAPIYAML lexer is YAML with OpenAPI/Swagger $refs, path templates and
+// operations highlighted.
+var OpenAPIYAML = Register(MustNewLexer(
+	&Config{
+		Name:      "OpenAPI YAML",
+		Aliases:   []string{"openapi", "openapi-yaml", "swagger", "swagger-yaml"},
+		Filenames: []string{"openapi.yaml", "openapi.yml", "swagger.yaml", "swagger.yml"},
+		MimeTypes: []string{"application/vnd.oai.openapi"},
+		// Higher than YAML, which claims all *.yaml files.
+		Priority: 2,
+	},
+	openAPIYAMLRules,
+).SetAnalyser(func(text string) float32 {
+	if openAPIYAMLRe.MatchString(text) {
+		return 0.8
+	}
+	return 0
+}))
+
+func openAPIYAMLRules() Rules {
+	yaml := Get("YAML").(*RegexLexer).MustRules()
+	return yaml.Merge(Rules{
+		"key": append([]Rule{
+			{`(\$ref)(:)([^\S\n]+)(["']?)([^"'\n]+)(\4)`, ByGroups(KeywordPseudo, Punctuation, TextWhitespace, LiteralStringOther, LiteralStringOther, LiteralStringOther), nil},
+			{openAPIMethods + `(:)(?=[ \n])`, ByGroups(Keyword, Punctuation), nil},
+			{`(?=/[^\n]*?:[ \n])`, Text, Push("path")},
+		}, yaml["key"]...),
+		"path": {
+			{`\{[^}\n]*\}`, NameVariable, nil},
+			{`[^{:\n]+`, NameTag, nil},
+			{`:`, Punctuation, Pop(1)},
+		},
+	})
+}
+
+// OpenAPIJSON lexer is JSON with OpenAPI/Swagger $refs, path templates and
+// operations highlighted.
+var OpenAPIJSON = Register(MustNewLexer(
+	&Config{
+		Name:         "OpenAPI JSON",
+		Aliases:      []string{"openapi-json", "swagger-json"},
+		Filenames:    []string{"openapi.json", "swagger.json"},
+		MimeTypes:    []string{"application/vnd.oai.openapi+json"},
+		DotAll:       true,
+		NotMultiline: true,
+		// Higher than JSON, which claims all *.json files.
+		Priority: 2,
+	},
+	openAPIJSONRules,
+).SetAnalyser(func(text string) float32 {
+	if openAPIJSONRe.MatchString(text) {
+		return 0.8
+	}
+	return 0
+}))
+
+func openAPIJSONRules() Rules {
+	json := Get("JSON").(*RegexLexer).MustRules()
+	return json.Merge(Rules{
+		"objectvalue": append([]Rule{
+			{`("\$ref")(\s*)(:)(\s*)("(?:\\\\|\\"|[^"])*")`, ByGroups(KeywordPseudo, Text, Punctuation, Text, LiteralStringOther), Push("objectattribute")},
+			{`"` + openAPIMethods + `"(?=\s*:)`, Keyword, Push("objectattribute")},
+			{`"(?=/)`, NameTag, Push("objectattribute", "path")},
+		}, json["objectvalue"]...),
+		"path": {
+			{`\{[^}"]*\}`, NameVariable, nil},
+			{`[^{"]+`, NameTag, nil},
+			{`"`, NameTag, Pop(1)},
+		},
+	})
+}
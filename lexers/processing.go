@@ -0,0 +1,30 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// Processing lexer, which extends the Java lexer rules with the Processing
+// framework's types, constants and built-in functions.
+var Processing = Register(MustNewLexer(
+	&Config{
+		Name:      "Processing",
+		Aliases:   []string{"processing"},
+		Filenames: []string{"*.pde"},
+		MimeTypes: []string{"text/x-processing"},
+		DotAll:    true,
+	},
+	processingRules,
+))
+
+func processingRules() Rules {
+	java := Get("Java").(*RegexLexer).MustRules()
+	return java.Merge(Rules{
+		"root": append([]Rule{
+			{`#[0-9a-fA-F]{6}\b`, LiteralNumberHex, nil},
+			{`\b(color|PImage|PFont|PGraphics|PShape|PVector|PApplet|IntList|FloatList|StringList|IntDict|FloatDict|StringDict|Table|TableRow|JSONObject|JSONArray|XML)\b`, KeywordType, nil},
+			{Words(`\b`, `\b`, `width`, `height`, `displayWidth`, `displayHeight`, `pixelWidth`, `pixelHeight`, `pixels`, `frameCount`, `frameRate`, `focused`, `mouseX`, `mouseY`, `pmouseX`, `pmouseY`, `mouseButton`, `mousePressed`, `key`, `keyCode`, `keyPressed`, `args`, `PI`, `HALF_PI`, `QUARTER_PI`, `TWO_PI`, `TAU`, `LEFT`, `RIGHT`, `CENTER`, `TOP`, `BOTTOM`, `BASELINE`, `UP`, `DOWN`, `CODED`, `SHIFT`, `CONTROL`, `ALT`, `ENTER`, `RETURN`, `BACKSPACE`, `TAB`, `DELETE`, `ESC`, `CORNER`, `CORNERS`, `RADIUS`, `RGB`, `HSB`, `ARGB`, `ALPHA`, `CLOSE`, `POINTS`, `LINES`, `TRIANGLES`, `TRIANGLE_FAN`, `TRIANGLE_STRIP`, `QUADS`, `QUAD_STRIP`, `P2D`, `P3D`, `JAVA2D`, `FX2D`, `PDF`, `SVG`, `ROUND`, `SQUARE`, `PROJECT`, `MITER`, `BEVEL`, `OPEN`, `CHORD`, `PIE`, `BLEND`, `ADD`, `SUBTRACT`, `MULTIPLY`, `SCREEN`, `GRAY`, `INVERT`, `THRESHOLD`, `BLUR`), NameBuiltin, nil},
+			{Words(`\b`, `\b(?=\s*\()`, `size`, `fullScreen`, `smooth`, `noSmooth`, `pixelDensity`, `settings`, `loop`, `noLoop`, `redraw`, `push`, `pop`, `pushMatrix`, `popMatrix`, `pushStyle`, `popStyle`, `translate`, `rotate`, `rotateX`, `rotateY`, `rotateZ`, `scale`, `background`, `clear`, `fill`, `noFill`, `stroke`, `noStroke`, `strokeWeight`, `strokeCap`, `strokeJoin`, `colorMode`, `tint`, `noTint`, `point`, `line`, `rect`, `rectMode`, `ellipse`, `ellipseMode`, `circle`, `square`, `arc`, `triangle`, `quad`, `bezier`, `curve`, `beginShape`, `endShape`, `vertex`, `curveVertex`, `bezierVertex`, `createShape`, `shape`, `loadShape`, `image`, `imageMode`, `loadImage`, `createImage`, `createGraphics`, `loadPixels`, `updatePixels`, `get`, `set`, `text`, `textSize`, `textFont`, `textAlign`, `textWidth`, `loadFont`, `createFont`, `println`, `print`, `printArray`, `random`, `randomSeed`, `noise`, `noiseSeed`, `map`, `constrain`, `dist`, `lerp`, `lerpColor`, `mag`, `norm`, `sq`, `sqrt`, `pow`, `abs`, `min`, `max`, `floor`, `ceil`, `round`, `sin`, `cos`, `tan`, `atan2`, `radians`, `degrees`, `millis`, `second`, `minute`, `hour`, `day`, `month`, `year`, `red`, `green`, `blue`, `alpha`, `hue`, `saturation`, `brightness`, `saveFrame`, `save`, `loadStrings`, `saveStrings`, `loadTable`, `loadJSONObject`, `loadXML`, `delay`, `exit`, `cursor`, `noCursor`), NameBuiltin, nil},
+		}, java["root"]...),
+	})
+}
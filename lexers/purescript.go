@@ -0,0 +1,36 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// PureScript lexer, which extends the Haskell lexer rules with PureScript
+// specific syntax.
+var PureScript = Register(MustNewLexer(
+	&Config{
+		Name:      "PureScript",
+		Aliases:   []string{"purescript", "purs"},
+		Filenames: []string{"*.purs"},
+		MimeTypes: []string{"text/x-purescript"},
+	},
+	pureScriptRules,
+))
+
+func pureScriptRules() Rules {
+	haskell := Get("Haskell").(*RegexLexer).MustRules()
+	return haskell.Merge(Rules{
+		"root": append([]Rule{
+			{`(foreign)(\s+)(import)(\s+)(data)(\s+)([\p{Lu}][\w']*)`, ByGroups(KeywordReserved, Text, KeywordReserved, Text, KeywordReserved, Text, KeywordType), nil},
+			{`(foreign)(\s+)(import)(\s+)([\w']+)`, ByGroups(KeywordReserved, Text, KeywordReserved, Text, NameFunction), nil},
+			{`\b(as)(\s+)([\p{Lu}][\w.]*)`, ByGroups(Keyword, Text, Name), nil},
+			{`\b(ado|derive|foreign)(?!')\b`, KeywordReserved, nil},
+			{`∀|∷|→|⇒|←`, OperatorWord, nil},
+			{`"""`, LiteralString, Push("tripleString")},
+			{`\?[_\p{Ll}][\w']*`, NameVariableMagic, nil},
+		}, haskell["root"]...),
+		"tripleString": {
+			{`"""(?!")`, LiteralString, Pop(1)},
+			{`[^"]+|"`, LiteralString, nil},
+		},
+	})
+}
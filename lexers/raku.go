@@ -26,7 +26,15 @@ var Raku Lexer = Register(MustNewLexer(
 		DotAll: true,
 	},
 	rakuRules,
-))
+).SetAnalyser(func(text string) float32 {
+	if rakuAnalyserRe.MatchString(text) {
+		return 1.0
+	}
+	return 0
+}))
+
+// Distinguishes Raku from Perl, which share the ".pl" and ".pm" extensions.
+var rakuAnalyserRe = regexp.MustCompile(`(?m)\A#!.*\b(?:raku|perl6)\b|^\s*use\s+v6\b|^\s*(?:unit\s+)?(?:grammar|role)\s+\w|^\s*(?:multi|proto)\s+(?:sub|method)\b`)
 
 func rakuRules() Rules {
 	type RakuToken int
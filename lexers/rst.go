@@ -28,7 +28,7 @@ func restructuredtextRules() Rules {
 			{`^(\s*)([A-Z]+\.)( .+\n(?:\1  .+\n)+)`, ByGroups(Text, LiteralNumber, UsingSelf("inline")), nil},
 			{`^(\s*)(\(?[A-Za-z]+\))( .+\n(?:\1  .+\n)+)`, ByGroups(Text, LiteralNumber, UsingSelf("inline")), nil},
 			{`^(\s*)(\|)( .+\n(?:\|  .+\n)*)`, ByGroups(Text, Operator, UsingSelf("inline")), nil},
-			{`^( *\.\.)(\s*)((?:source)?code(?:-block)?)(::)([ \t]*)([^\n]+)(\n[ \t]*\n)([ \t]+)(.*)(\n)((?:(?:\8.*|)\n)+)`, EmitterFunc(rstCodeBlock), nil},
+			{`^( *\.\.)(\s*)((?:source)?code(?:-block)?)(::)([ \t]*)([^\n]*)((?:\n[ \t]+:[\w-]+:.*)*)(\n[ \t]*\n)([ \t]+)(.*)(\n)((?:(?:\9.*|)\n)+)`, EmitterFunc(rstCodeBlock), nil},
 			{`^( *\.\.)(\s*)([\w:-]+?)(::)(?:([ \t]*)(.*))`, ByGroups(Punctuation, Text, OperatorWord, Punctuation, Text, UsingSelf("inline")), nil},
 			{`^( *\.\.)(\s*)(_(?:[^:\\]|\\.)+:)(.*?)$`, ByGroups(Punctuation, Text, NameTag, UsingSelf("inline")), nil},
 			{`^( *\.\.)(\s*)(\[.+\])(.*?)$`, ByGroups(Punctuation, Text, NameTag, UsingSelf("inline")), nil},
@@ -52,6 +52,7 @@ func restructuredtextRules() Rules {
 			{`\[.*?\]_`, LiteralString, nil},
 			{`<.+?>`, NameTag, nil},
 			{"[^\\\\\\n\\[*`:]+", Text, nil},
+			{`\n`, Text, nil},
 			{`.`, Text, nil},
 		},
 		"literal": {
@@ -64,16 +65,24 @@ func restructuredtextRules() Rules {
 
 func rstCodeBlock(groups []string, state *LexerState) Iterator {
 	iterators := []Iterator{}
-	tokens := []Token{
-		{Punctuation, groups[1]},
-		{Text, groups[2]},
-		{OperatorWord, groups[3]},
-		{Punctuation, groups[4]},
-		{Text, groups[5]},
-		{Keyword, groups[6]},
-		{Text, groups[7]},
+	tokens := []Token{}
+	// The whitespace and language are absent from a bare ".. code::".
+	for i, tokenType := range []TokenType{Punctuation, Text, OperatorWord, Punctuation, Text, Keyword} {
+		if groups[i+1] != "" {
+			tokens = append(tokens, Token{tokenType, groups[i+1]})
+		}
+	}
+	iterators = append(iterators, Literator(tokens...))
+	// Directive options such as ":linenos:" are lexed as a field list.
+	if groups[7] != "" {
+		options, err := state.Lexer.Tokenise(&TokeniseOptions{State: "root", Nested: true}, groups[7])
+		if err != nil {
+			panic(err)
+		}
+		iterators = append(iterators, options)
 	}
-	code := strings.Join(groups[8:], "")
+	tokens = []Token{{Text, groups[8]}}
+	code := strings.Join(groups[9:], "")
 	lexer := Get(groups[6])
 	if lexer == nil {
 		tokens = append(tokens, Token{String, code})
@@ -0,0 +1,18 @@
+package lexers
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestRstBareCodeBlock(t *testing.T) {
+	it, err := Get("rst").Tokenise(nil, ".. code::\n\n   x = 1\n\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []chroma.Token{
+		{chroma.Punctuation, ".."}, {chroma.Text, " "}, {chroma.OperatorWord, "code"},
+		{chroma.Punctuation, "::"}, {chroma.Text, "\n\n"}, {chroma.LiteralString, "   x = 1\n\n"},
+	}, it.Tokens())
+}
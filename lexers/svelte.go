@@ -53,7 +53,7 @@ func svelteRules() Rules {
 			{`(?<!(?<!\\)\\)(['"` + "`])" + `.*?(?<!(?<!\\)\\)\1`, Using("TypeScript"), nil},
 			// If there is another opening curly brace push to templates again
 			{"{", Punctuation, Push("templates")},
-			{`@(debug|html)\b`, Keyword, nil},
+			{`@(const|debug|html|render)\b`, Keyword, nil},
 			{
 				`(#await)(\s+)(\w+)(\s+)(then|catch)(\s+)(\w+)`,
 				ByGroups(Keyword, Text, Using("TypeScript"), Text,
@@ -61,7 +61,7 @@ func svelteRules() Rules {
 				),
 				nil,
 			},
-			{`(#|/)(await|each|if|key)\b`, Keyword, nil},
+			{`(#|/)(await|each|if|key|snippet)\b`, Keyword, nil},
 			{`(:else)(\s+)(if)?\b`, ByGroups(Keyword, Text, Keyword), nil},
 			{`:(catch|then)\b`, Keyword, nil},
 			{`[^{}]+`, Using("TypeScript"), nil},
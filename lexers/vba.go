@@ -0,0 +1,45 @@
+package lexers
+
+import (
+	"regexp"
+
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+var vbaAnalyserRe = regexp.MustCompile(`(?m)^(?:Attribute VB_Name\s*=|VERSION \d+\.\d+ CLASS)`)
+
+// VBA lexer, which extends the VB.net lexer rules with the classic Visual
+// Basic for Applications module syntax.
+var VBA = Register(MustNewLexer(
+	&Config{
+		Name:            "VBA",
+		Aliases:         []string{"vba", "vb6"},
+		Filenames:       []string{"*.vba", "*.frm"},
+		AliasFilenames:  []string{"*.bas", "*.cls"},
+		MimeTypes:       []string{"text/x-vba"},
+		CaseInsensitive: true,
+	},
+	vbaRules,
+).SetAnalyser(func(text string) float32 {
+	if vbaAnalyserRe.MatchString(text) {
+		return 0.9
+	}
+	return 0
+}))
+
+func vbaRules() Rules {
+	vbnet := Get("VB.net").(*RegexLexer).MustRules()
+	return vbnet.Merge(Rules{
+		"root": append([]Rule{
+			{`^\d+(?=\s)`, NameLabel, nil},
+			{`^(Attribute)(\s+)(VB_\w+)`, ByGroups(Keyword, Text, NameAttribute), nil},
+			{`^(VERSION)(\s+)(\d+\.\d+)(\s+)(CLASS)\b`, ByGroups(Keyword, Text, LiteralNumberFloat, Text, Keyword), nil},
+			{`^(BEGIN|END)\b(?=\s*$)`, Keyword, nil},
+			{`(?<!\.)(End)(\s+)(Type)\b`, ByGroups(Keyword, Text, Keyword), nil},
+			{`(?<!\.)(Type)(\s+)`, ByGroups(Keyword, Text), Push("classname")},
+			{`(?<!\.)(PtrSafe|Preserve|Until|Me|Open|Close|Print|Append|Output|Binary|Random|DefBool|DefByte|DefInt|DefLng|DefCur|DefSng|DefDbl|DefDate|DefStr|DefObj|DefVar)\b`, Keyword, nil},
+			{`(?<!\.)(Currency|LongLong|LongPtr|Collection)\b`, KeywordType, nil},
+			{`(?<!\.)(MsgBox|InputBox|Debug|Err|CreateObject|GetObject|Application|ThisWorkbook|ThisDocument|ActiveWorkbook|ActiveSheet|ActiveDocument)\b`, NameBuiltin, nil},
+		}, vbnet["root"]...),
+	})
+}
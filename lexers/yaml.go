@@ -0,0 +1,25 @@
+package lexers
+
+import (
+	. "github.com/alecthomas/chroma/v2" // nolint
+)
+
+// YAML lexer.
+var YAML = MustNewXMLLexer(embedded, "embedded/yaml.xml")
+
+// YAMLJinja lexer is Jinja2 expressions embedded in YAML, as used by Ansible
+// playbooks and Salt states.
+var YAMLJinja = Register(DelegatingLexer(YAML, MustNewXMLLexer(
+	embedded,
+	"embedded/django_jinja.xml",
+).SetConfig(
+	&Config{
+		Name:      "YAML+Jinja",
+		Aliases:   []string{"yaml+jinja", "ansible", "salt", "sls"},
+		Filenames: []string{"*.sls", "*.yaml.j2", "*.yml.j2", "*.yaml.jinja2", "*.yml.jinja2"},
+		MimeTypes: []string{"text/x-yaml+jinja", "text/x-sls"},
+		DotAll:    true,
+		// Higher than Django/Jinja, which claims all *.j2 files.
+		Priority: 2,
+	},
+)))
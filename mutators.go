@@ -109,6 +109,30 @@ func (i *includeMutator) MutateLexer(rules CompiledRules, state string, rule int
 	return nil
 }
 
+type longestMatchMutator struct{}
+
+// LongestMatch makes the state containing it match the rule with the longest match at each
+// position, rather than the first rule that matches. Rules with equally long matches are still
+// prioritised in order, so keyword rules can precede a general identifier rule without matching
+// the prefix of a longer identifier.
+//
+// Rules included into the state are part of the contest, but including a state containing
+// LongestMatch does not make the including state match the longest rule.
+func LongestMatch() Rule {
+	return Rule{Mutator: &longestMatchMutator{}}
+}
+
+func (l *longestMatchMutator) MutatorKind() string { return "longestmatch" }
+
+func (l *longestMatchMutator) Mutate(s *LexerState) error {
+	return fmt.Errorf("should never reach here LongestMatch()")
+}
+
+func (l *longestMatchMutator) MutateLexer(rules CompiledRules, state string, rule int) error {
+	rules[state] = append(rules[state][:rule], rules[state][rule+1:]...)
+	return nil
+}
+
 type combinedMutator struct {
 	States []string `xml:"state,attr"`
 }
@@ -55,3 +55,27 @@ func TestCombine(t *testing.T) {
 	expected := []Token{{String, `hello`}, {Whitespace, ` `}, {Name, `world`}}
 	assert.Equal(t, expected, it.Tokens())
 }
+
+func TestLongestMatch(t *testing.T) {
+	rules := Rules{
+		"root": {
+			{`\s+`, Whitespace, nil},
+			Include("keywords"),
+			{`\w+`, Name, nil},
+		},
+		"keywords": {
+			{`if|in`, Keyword, nil},
+		},
+	}
+	lexer := mustNewLexer(t, nil, rules)
+	it, err := lexer.Tokenise(nil, "if index")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{{Keyword, "if"}, {Whitespace, " "}, {Keyword, "in"}, {Name, "dex"}}, it.Tokens())
+
+	rules = rules.Clone()
+	rules["root"] = append([]Rule{LongestMatch()}, rules["root"]...)
+	lexer = mustNewLexer(t, nil, rules)
+	it, err = lexer.Tokenise(nil, "if index")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{{Keyword, "if"}, {Whitespace, " "}, {Name, "index"}}, it.Tokens())
+}
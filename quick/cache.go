@@ -0,0 +1,87 @@
+package quick
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"sync"
+)
+
+// Cache is a least recently used cache of highlighted output, for servers that repeatedly
+// render the same files.
+//
+// A Cache is safe for concurrent use.
+type Cache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List // Most recently used first.
+}
+
+type cacheKey struct {
+	hash                    [sha256.Size]byte
+	lexer, formatter, style string
+}
+
+type cacheEntry struct {
+	key    cacheKey
+	output []byte
+}
+
+// NewCache creates a Cache holding the output of at most size highlights.
+func NewCache(size int) *Cache {
+	return &Cache{size: size, entries: map[cacheKey]*list.Element{}, order: list.New()}
+}
+
+// Highlight highlights source to w like the package level Highlight function, caching the
+// output. Highlighting the same source with the same lexer, formatter and style again writes
+// the cached output.
+func (c *Cache) Highlight(w io.Writer, source, lexer, formatter, style string) error {
+	key := cacheKey{sha256.Sum256([]byte(source)), lexer, formatter, style}
+	c.mu.Lock()
+	element, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	c.mu.Unlock()
+	if ok {
+		_, err := w.Write(element.Value.(*cacheEntry).output)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Highlight(buf, source, lexer, formatter, style); err != nil {
+		return err
+	}
+	c.add(key, buf.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Len returns the number of cached highlights.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) add(key cacheKey, output []byte) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		// Highlighted concurrently.
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key, output})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,37 @@
+package quick
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(2)
+	highlight := func(source, lexer string) string {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		assert.NoError(t, cache.Highlight(buf, source, lexer, "html", "monokai"))
+		return buf.String()
+	}
+	expected := &bytes.Buffer{}
+	assert.NoError(t, Highlight(expected, "package main\n", "go", "html", "monokai"))
+
+	assert.Equal(t, expected.String(), highlight("package main\n", "go"))
+	assert.Equal(t, expected.String(), highlight("package main\n", "go"))
+	assert.Equal(t, 1, cache.Len())
+
+	assert.NotEqual(t, expected.String(), highlight("package main\n", "plaintext"))
+	assert.Equal(t, 2, cache.Len())
+
+	// Touch the Go entry, so the plaintext entry is evicted.
+	highlight("package main\n", "go")
+	highlight("x = 1\n", "python")
+	assert.Equal(t, 2, cache.Len())
+	_, ok := cache.entries[cacheKey{sha256.Sum256([]byte("package main\n")), "go", "html", "monokai"}]
+	assert.True(t, ok)
+	_, ok = cache.entries[cacheKey{sha256.Sum256([]byte("package main\n")), "plaintext", "html", "monokai"}]
+	assert.False(t, ok)
+}
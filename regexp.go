@@ -84,6 +84,25 @@ func MustNewLexer(config *Config, rules func() Rules) *RegexLexer {
 	return lexer
 }
 
+// NewLexerStrict creates a new regex-based Lexer like NewLexer, but compiles its rules
+// immediately rather than when it is first used, and checks that ByGroups and UsingByGroup
+// emitters have one emitter per capturing group of their rule.
+//
+// Errors in rules are returned as a *RuleError.
+func NewLexerStrict(config *Config, rulesFunc func() Rules) (*RegexLexer, error) {
+	lexer, err := NewLexer(config, rulesFunc)
+	if err != nil {
+		return nil, err
+	}
+	if err := lexer.needRules(); err != nil {
+		return nil, err
+	}
+	if err := checkGroupArity(lexer.config.Name, lexer.rawRules, lexer.ruleFlags()); err != nil {
+		return nil, err
+	}
+	return lexer, nil
+}
+
 // NewLexer creates a new regex-based Lexer.
 //
 // "rules" is a state machine transition map. Each key is a state. Values are sets of rules
@@ -134,6 +153,39 @@ func NewLexer(config *Config, rulesFunc func() Rules) (*RegexLexer, error) {
 	return r, nil
 }
 
+// AddPostProcessor returns a copy of the lexer with an additional hook applied to every token it
+// emits, after any hooks already added, for language-specific cleanups such as reclassifying
+// builtins. The lexer itself, which may be shared, such as one from the lexers package, is not
+// modified.
+//
+// A hook returns the token to emit in place of the given token, which it may modify, or nil to
+// drop it. Hooks apply to the tokens emitted by the lexer's token type emitters and its Error
+// tokens, and not to those of other lexers it delegates to, such as with Using.
+func (r *RegexLexer) AddPostProcessor(processor func(*Token) *Token) *RegexLexer {
+	processors := make([]func(*Token) *Token, 0, len(r.postProcessors)+1)
+	processors = append(append(processors, r.postProcessors...), processor)
+	return &RegexLexer{
+		registry:       r.registry,
+		config:         r.config,
+		analyser:       r.analyser,
+		trace:          r.trace,
+		postProcessors: processors,
+		fetchRulesFunc: r.fetchRulesFunc,
+	}
+}
+
+// postProcess applies the post-processors to a token emitted by the lexer, returning false if
+// the token is dropped.
+func (r *RegexLexer) postProcess(token Token) (Token, bool) {
+	processed := &token
+	for _, processor := range r.postProcessors {
+		if processed = processor(processed); processed == nil {
+			return Token{}, false
+		}
+	}
+	return *processed, true
+}
+
 // Trace enables debug tracing.
 func (r *RegexLexer) Trace(trace bool) *RegexLexer {
 	r.trace = trace
@@ -171,6 +223,7 @@ type LexerState struct {
 	iteratorStack  []Iterator
 	options        *TokeniseOptions
 	newlineAdded   bool
+	previous       Token
 }
 
 // Set mutator context.
@@ -183,8 +236,25 @@ func (l *LexerState) Get(key interface{}) interface{} {
 	return l.MutatorContext[key]
 }
 
+// Previous returns the last token emitted before the current match that is not entirely
+// whitespace, or EOF if there is none.
+//
+// Emitters can use it for context-sensitive emission, such as typing an identifier following
+// "func" as NameFunction.
+func (l *LexerState) Previous() Token {
+	return l.previous
+}
+
 // Iterator returns the next Token from the lexer.
-func (l *LexerState) Iterator() Token { // nolint: gocognit
+func (l *LexerState) Iterator() Token {
+	token := l.next()
+	if strings.TrimSpace(token.Value) != "" {
+		l.previous = token
+	}
+	return token
+}
+
+func (l *LexerState) next() Token { // nolint: gocognit
 	end := len(l.Text)
 	if l.newlineAdded {
 		end--
@@ -212,7 +282,7 @@ func (l *LexerState) Iterator() Token { // nolint: gocognit
 		if !ok {
 			panic("unknown state " + l.State)
 		}
-		ruleIndex, rule, groups, namedGroups := matchRules(l.Text, l.Pos, selectedRule)
+		ruleIndex, rule, groups, namedGroups := matchRules(l.Text, l.Pos, selectedRule, l.Lexer.longestMatch[l.State])
 		// No match.
 		if groups == nil {
 			// From Pygments :\
@@ -226,7 +296,13 @@ func (l *LexerState) Iterator() Token { // nolint: gocognit
 				continue
 			}
 			l.Pos++
-			return Token{Error, string(l.Text[l.Pos-1 : l.Pos])}
+			if token, ok := l.Lexer.postProcess(Token{Error, string(l.Text[l.Pos-1 : l.Pos])}); ok {
+				return token
+			}
+			continue
+		}
+		if l.options.Coverage != nil {
+			l.options.Coverage.record(l.Lexer, l.State, ruleIndex)
 		}
 		l.Rule = ruleIndex
 		l.Groups = groups
@@ -260,7 +336,9 @@ func (l *LexerState) Iterator() Token { // nolint: gocognit
 	if l.Pos != len(l.Text) && len(l.Stack) == 0 {
 		value := string(l.Text[l.Pos:])
 		l.Pos = len(l.Text)
-		return Token{Type: Error, Value: value}
+		if token, ok := l.Lexer.postProcess(Token{Type: Error, Value: value}); ok {
+			return token
+		}
 	}
 	return EOF
 }
@@ -272,10 +350,13 @@ type RegexLexer struct {
 	analyser func(text string) float32
 	trace    bool
 
+	postProcessors []func(*Token) *Token
+
 	mu             sync.Mutex
 	compiled       bool
 	rawRules       Rules
 	rules          map[string][]*CompiledRule
+	longestMatch   map[string]bool // States containing a LongestMatch() rule.
 	fetchRulesFunc func() (Rules, error)
 	compileOnce    sync.Once
 }
@@ -340,7 +421,7 @@ func (r *RegexLexer) maybeCompile() (err error) {
 				pattern = `\G` + pattern
 				rule.Regexp, err = regexp2.Compile(pattern, 0)
 				if err != nil {
-					return fmt.Errorf("failed to compile rule %s.%d: %s", state, i, err)
+					return newRuleError(r.config.Name, state, i, rule, err)
 				}
 				rule.Regexp.MatchTimeout = time.Millisecond * 250
 			}
@@ -379,18 +460,13 @@ func (r *RegexLexer) fetchRules() error {
 		return fmt.Errorf("no \"root\" state")
 	}
 	compiledRules := map[string][]*CompiledRule{}
+	longestMatch := map[string]bool{}
+	flags := r.ruleFlags()
 	for state, rules := range rules {
 		compiledRules[state] = nil
 		for _, rule := range rules {
-			flags := ""
-			if !r.config.NotMultiline {
-				flags += "m"
-			}
-			if r.config.CaseInsensitive {
-				flags += "i"
-			}
-			if r.config.DotAll {
-				flags += "s"
+			if _, ok := rule.Mutator.(*longestMatchMutator); ok {
+				longestMatch[state] = true
 			}
 			compiledRules[state] = append(compiledRules[state], &CompiledRule{Rule: rule, flags: flags})
 		}
@@ -398,9 +474,25 @@ func (r *RegexLexer) fetchRules() error {
 
 	r.rawRules = rules
 	r.rules = compiledRules
+	r.longestMatch = longestMatch
 	return nil
 }
 
+// ruleFlags returns the regex flags the config applies to every rule.
+func (r *RegexLexer) ruleFlags() string {
+	flags := ""
+	if !r.config.NotMultiline {
+		flags += "m"
+	}
+	if r.config.CaseInsensitive {
+		flags += "i"
+	}
+	if r.config.DotAll {
+		flags += "s"
+	}
+	return flags
+}
+
 func (r *RegexLexer) needRules() error {
 	var err error
 	if r.fetchRulesFunc != nil {
@@ -453,20 +545,34 @@ func (r *RegexLexer) MustRules() Rules {
 	return rules
 }
 
-func matchRules(text []rune, pos int, rules []*CompiledRule) (int, *CompiledRule, []string, map[string]string) {
+// matchRules returns the first rule matching text at pos or, if longest is true, the first of the
+// rules with the longest match.
+func matchRules(text []rune, pos int, rules []*CompiledRule, longest bool) (int, *CompiledRule, []string, map[string]string) {
+	var (
+		best      *regexp2.Match
+		bestIndex int
+	)
 	for i, rule := range rules {
 		match, err := rule.Regexp.FindRunesMatchStartingAt(text, pos)
 		if match != nil && err == nil && match.Index == pos {
-			groups := []string{}
-			namedGroups := make(map[string]string)
-			for _, g := range match.Groups() {
-				namedGroups[g.Name] = g.String()
-				groups = append(groups, g.String())
+			if best == nil || match.Length > best.Length {
+				best, bestIndex = match, i
+			}
+			if !longest || pos+match.Length == len(text) {
+				break
 			}
-			return i, rule, groups, namedGroups
 		}
 	}
-	return 0, &CompiledRule{}, nil, nil
+	if best == nil {
+		return 0, &CompiledRule{}, nil, nil
+	}
+	groups := []string{}
+	namedGroups := make(map[string]string)
+	for _, g := range best.Groups() {
+		namedGroups[g.Name] = g.String()
+		groups = append(groups, g.String())
+	}
+	return bestIndex, rules[bestIndex], groups, namedGroups
 }
 
 // replace \r and \r\n with \n
@@ -0,0 +1,167 @@
+package chroma
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+	"github.com/dlclark/regexp2/syntax"
+)
+
+// RuleError is an error in a rule of a RegexLexer, such as a pattern that does not compile.
+type RuleError struct {
+	Lexer string
+	State string
+	// Index of the rule within its state.
+	Index   int
+	Pattern string
+	// Offset in bytes of the offending sub-expression of Pattern, or -1 if unknown.
+	Offset int
+	// Expr is the offending sub-expression, if known.
+	Expr string
+	// Suggestion for fixing the rule, if the error is a known construct that the regexp2
+	// engine does not support.
+	Suggestion string
+	Err        error
+}
+
+func (e *RuleError) Error() string {
+	msg := fmt.Sprintf("failed to compile rule %s.%d", e.State, e.Index)
+	if e.Lexer != "" {
+		msg = e.Lexer + ": " + msg
+	}
+	if e.Offset >= 0 {
+		msg += fmt.Sprintf(" at offset %d (%q)", e.Offset, e.Expr)
+	}
+	msg += ": " + e.Err.Error()
+	if e.Suggestion != "" {
+		msg += "; " + e.Suggestion
+	}
+	return msg
+}
+
+func (e *RuleError) Unwrap() error { return e.Err }
+
+// newRuleError diagnoses the failure to compile the pattern of a rule.
+func newRuleError(lexer, state string, index int, rule *CompiledRule, err error) *RuleError {
+	ruleErr := &RuleError{Lexer: lexer, State: state, Index: index, Pattern: rule.Pattern, Offset: -1, Err: err}
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		return ruleErr
+	}
+	flags := ""
+	if rule.flags != "" {
+		flags = "(?" + rule.flags + ")"
+	}
+	sameError := func(pattern string) bool {
+		_, err := regexp2.Compile(flags+pattern, 0)
+		var other *syntax.Error
+		return errors.As(err, &other) && other.Code == syntaxErr.Code && reflect.DeepEqual(other.Args, syntaxErr.Args)
+	}
+	switch syntaxErr.Code {
+	case syntax.ErrMissingParen, syntax.ErrMissingBrace, syntax.ErrUnterminatedBracket,
+		syntax.ErrUnterminatedComment, syntax.ErrIllegalEndEscape:
+		// Unterminated constructs are only detected at the end of the pattern.
+		return ruleErr
+	}
+	// The offending sub-expression ends where the shortest failing prefix of the pattern ends,
+	// and starts where the shortest failing suffix of that prefix starts.
+	end := -1
+	for i := range rule.Pattern {
+		if i > 0 && sameError(rule.Pattern[:i]) {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		if !sameError(rule.Pattern) {
+			return ruleErr
+		}
+		end = len(rule.Pattern)
+	}
+	start := 0
+	for i := range rule.Pattern[:end] {
+		if sameError(rule.Pattern[i:end]) {
+			start = i
+		}
+	}
+	ruleErr.Offset = start
+	ruleErr.Expr = rule.Pattern[start:end]
+	ruleErr.Suggestion = suggestRuleFix(syntaxErr.Code, rule.Pattern[start:])
+	return ruleErr
+}
+
+// ruleFixes are suggestions for constructs from other regex dialects, such as PCRE and Python,
+// keyed by the text they start with. Lookbehind and backreferences are supported by regexp2, and
+// need no suggestion.
+var ruleFixes = []struct {
+	prefix, suggestion string
+}{
+	{`(?P<`, `use (?<name>...) for named groups`},
+	{`(?P=`, `use \k<name> for named backreferences`},
+	{`(?P>`, `recursion is not supported`},
+	{`(?R`, `recursion is not supported`},
+	{`(?&`, `recursion is not supported`},
+	{`(?|`, `branch reset groups are not supported`},
+	{`\h`, `use [ \t] for horizontal whitespace`},
+	{`\K`, `use a lookbehind (?<=...) to match text before the token`},
+	{`\Q`, `escape metacharacters individually, as \Q...\E is not supported`},
+	{`\R`, `use (?:\r\n|\n|\r) for line breaks`},
+	{`\N`, `use [^\n] for any character but a newline`},
+	{`\o`, `use \x or \u escapes for character codes`},
+}
+
+func suggestRuleFix(code syntax.ErrorCode, expr string) string {
+	if code == syntax.ErrInvalidRepeatOp && strings.Contains(expr, "+") {
+		return "possessive quantifiers are not supported; use an atomic group such as (?>a+)"
+	}
+	if code == syntax.ErrUnrecognizedGrouping && len(expr) > 2 && expr[2] >= '0' && expr[2] <= '9' {
+		return "recursion is not supported"
+	}
+	for _, fix := range ruleFixes {
+		if strings.HasPrefix(expr, fix.prefix) {
+			return fix.suggestion
+		}
+	}
+	return ""
+}
+
+// checkGroupArity checks that rules emitting ByGroups or UsingByGroup have one emitter per
+// capturing group in their pattern.
+func checkGroupArity(lexer string, rules Rules, flags string) error {
+	for state, stateRules := range rules {
+		for i, rule := range stateRules {
+			var emitters Emitters
+			switch emitter := rule.Type.(type) {
+			case *byGroupsEmitter:
+				emitters = emitter.Emitters
+			case *usingByGroup:
+				emitters = emitter.Emitters
+			default:
+				continue
+			}
+			pattern := rule.Pattern
+			if flags != "" {
+				pattern = "(?" + flags + ")" + pattern
+			}
+			re, err := regexp2.Compile(pattern, 0)
+			if err != nil {
+				return newRuleError(lexer, state, i, &CompiledRule{Rule: rule, flags: flags}, err)
+			}
+			if groups := len(re.GetGroupNumbers()) - 1; groups != len(emitters) {
+				return &RuleError{
+					Lexer:      lexer,
+					State:      state,
+					Index:      i,
+					Pattern:    rule.Pattern,
+					Offset:     -1,
+					Err:        fmt.Errorf("%d emitters for %d capturing groups", len(emitters), groups),
+					Suggestion: "use (?:...) for groups that should not be emitted",
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package chroma
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	assert "github.com/alecthomas/assert/v2"
@@ -203,3 +205,146 @@ func TestIgnoreToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []Token{{Keyword, "hello"}, {TextWhitespace, "\n"}}, it.Tokens())
 }
+
+func TestRuleErrorDiagnostics(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		offset     int
+		expr       string
+		suggestion string
+	}{
+		{`foo\hbar`, 3, `\h`, `use [ \t] for horizontal whitespace`},
+		{`(?P<name>\w+)`, 0, `(?P`, `use (?<name>...) for named groups`},
+		{`"(\\.|[^"])*+"`, 1, `(\\.|[^"])*+`, "possessive quantifiers are not supported; use an atomic group such as (?>a+)"},
+		{`(a)(?1)`, 3, `(?1`, "recursion is not supported"},
+		{`\p{Foo}`, 0, `\p{Foo}`, ""},
+		{`(a`, -1, "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			lexer := mustNewLexer(t, &Config{Name: "Test"}, Rules{
+				"root": {
+					{`\s+`, Whitespace, nil},
+					{test.pattern, Keyword, nil},
+				},
+			})
+			_, err := lexer.Tokenise(nil, "text")
+			var ruleErr *RuleError
+			assert.True(t, errors.As(err, &ruleErr), "%v", err)
+			assert.Equal(t, "root", ruleErr.State)
+			assert.Equal(t, 1, ruleErr.Index)
+			assert.Equal(t, test.offset, ruleErr.Offset)
+			assert.Equal(t, test.expr, ruleErr.Expr)
+			assert.Equal(t, test.suggestion, ruleErr.Suggestion)
+		})
+	}
+}
+
+func TestNewLexerStrict(t *testing.T) {
+	_, err := NewLexerStrict(&Config{Name: "Test"}, func() Rules {
+		return Rules{
+			"root": {
+				{`(\w+)(\s*)(=)`, ByGroups(Name, Whitespace, Operator), nil},
+				{`(?<=\s)(\w)\1`, ByGroups(Keyword), nil},
+			},
+		}
+	})
+	assert.NoError(t, err)
+
+	_, err = NewLexerStrict(&Config{Name: "Test"}, func() Rules {
+		return Rules{
+			"root": {
+				{`\s+`, Whitespace, nil},
+				{`(\w+)(\s*)(=)`, ByGroups(Name, Operator), nil},
+			},
+		}
+	})
+	assert.EqualError(t, err, "Test: failed to compile rule root.1: 2 emitters for 3 capturing groups; use (?:...) for groups that should not be emitted")
+
+	_, err = NewLexerStrict(&Config{Name: "Test"}, func() Rules {
+		return Rules{"root": {{`a\Kb`, Keyword, nil}}}
+	})
+	assert.EqualError(t, err, "Test: failed to compile rule root.0 at offset 1 (\"\\\\K\"): error parsing regexp: unrecognized escape sequence \\K in `\\G(?m)(?:a\\Kb)`; use a lookbehind (?<=...) to match text before the token")
+}
+
+func TestLexerStatePrevious(t *testing.T) {
+	identifier := EmitterFunc(func(groups []string, state *LexerState) Iterator {
+		if previous := state.Previous(); previous.Type == Keyword && previous.Value == "func" {
+			return NameFunction.Emit(groups, state)
+		}
+		return Name.Emit(groups, state)
+	})
+	lexer := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`\s+`, Whitespace, nil},
+			{`func\b`, Keyword, nil},
+			{`\w+`, identifier, nil},
+			{`\(\)`, Punctuation, nil},
+		},
+	})
+	it, err := lexer.Tokenise(nil, "func  main() main")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{
+		{Keyword, "func"}, {Whitespace, "  "}, {NameFunction, "main"}, {Punctuation, "()"},
+		{Whitespace, " "}, {Name, "main"},
+	}, it.Tokens())
+}
+
+func TestAddPostProcessor(t *testing.T) {
+	builtins := map[string]bool{"len": true, "print": true}
+	lexer := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`\s+`, Whitespace, nil},
+			{`#.*`, Comment, nil},
+			{`\w+`, Name, nil},
+		},
+	})
+	processed := lexer.AddPostProcessor(func(token *Token) *Token {
+		if token.Type == Name && builtins[token.Value] {
+			token.Type = NameBuiltin
+		}
+		return token
+	}).AddPostProcessor(func(token *Token) *Token {
+		if token.Type == Comment {
+			return nil
+		}
+		return token
+	})
+	it, err := processed.Tokenise(nil, "print x # note\nlen")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{
+		{NameBuiltin, "print"}, {Whitespace, " "}, {Name, "x"}, {Whitespace, " "},
+		{Whitespace, "\n"}, {NameBuiltin, "len"},
+	}, it.Tokens())
+
+	// The original lexer is unchanged.
+	it, err = lexer.Tokenise(nil, "print")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{{Name, "print"}}, it.Tokens())
+}
+
+func TestAddPostProcessorDelegation(t *testing.T) {
+	inner := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`\w+`, Name, nil},
+			{`[^\w]+`, Punctuation, nil},
+		},
+	})
+	upper := func(token *Token) *Token {
+		token.Value = strings.ToUpper(token.Value)
+		return token
+	}
+	outer := mustNewLexer(t, nil, Rules{
+		"root": {
+			{`(<)(.*?)(>)`, ByGroups(Punctuation, UsingLexer(inner), Punctuation), nil},
+			{`\w+`, Name, nil},
+			{`\s+`, Whitespace, nil},
+		},
+	}).AddPostProcessor(upper)
+	it, err := outer.Tokenise(nil, "outer <inner;>")
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{
+		{Name, "OUTER"}, {Whitespace, " "}, {Punctuation, "<"},
+		{Name, "inner"}, {Punctuation, ";"}, {Punctuation, ">"},
+	}, it.Tokens())
+}
@@ -1,6 +1,8 @@
 package chroma
 
 import (
+	"fmt"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -21,9 +23,15 @@ var (
 
 // LexerRegistry is a registry of Lexers.
 type LexerRegistry struct {
-	Lexers  Lexers
-	byName  map[string]Lexer
-	byAlias map[string]Lexer
+	Lexers    Lexers
+	byName    map[string]Lexer
+	byAlias   map[string]Lexer
+	overrides []filenameOverride
+}
+
+type filenameOverride struct {
+	glob  string
+	lexer Lexer
 }
 
 // NewLexerRegistry creates a new LexerRegistry of Lexers.
@@ -100,39 +108,95 @@ func (l *LexerRegistry) MatchMimeType(mimeType string) Lexer {
 //
 // Note that this iterates over all file patterns in all lexers, so is not fast.
 func (l *LexerRegistry) Match(filename string) Lexer {
+	if lexer := l.matchOverride(filename); lexer != nil {
+		return lexer
+	}
 	filename = filepath.Base(filename)
-	matched := PrioritisedLexers{}
-	// First, try primary filename matches.
-	for _, lexer := range l.Lexers {
-		config := lexer.Config()
-		for _, glob := range config.Filenames {
-			ok, err := filepath.Match(glob, filename)
-			if err != nil { // nolint
-				panic(err)
-			} else if ok {
-				matched = append(matched, lexer)
-			} else {
-				for _, suf := range &ignoredSuffixes {
-					ok, err := filepath.Match(glob+suf, filename)
-					if err != nil {
-						panic(err)
-					} else if ok {
-						matched = append(matched, lexer)
-						break
-					}
+	// First, try primary filename matches, then filename aliases.
+	for _, aliases := range []bool{false, true} {
+		if matched := l.matchFilename(filename, aliases); len(matched) > 0 {
+			sort.Sort(matched)
+			return matched[0]
+		}
+	}
+	return nil
+}
+
+// MatchContent is like Match, but when several lexers match filename, as is common for
+// extensions such as ".h", ".m" and ".pl", it picks the candidate whose analyser scores
+// text highest.
+//
+// If no candidate scores above zero the result is the same as Match.
+func (l *LexerRegistry) MatchContent(filename, text string) Lexer {
+	if lexer := l.matchOverride(filename); lexer != nil {
+		return lexer
+	}
+	filename = filepath.Base(filename)
+	for _, aliases := range []bool{false, true} {
+		matched := l.matchFilename(filename, aliases)
+		if len(matched) == 0 {
+			continue
+		}
+		sort.Sort(matched)
+		picked := matched[0]
+		highest := float32(0.0)
+		for _, lexer := range matched {
+			if analyser, ok := lexer.(Analyser); ok {
+				if weight := analyser.AnalyseText(text); weight > highest {
+					picked = lexer
+					highest = weight
 				}
 			}
 		}
+		return picked
 	}
-	if len(matched) > 0 {
-		sort.Sort(matched)
-		return matched[0]
+	return nil
+}
+
+// OverrideFilename maps filenames matching glob to lexer, taking precedence over the filename
+// patterns of all registered lexers in Match and MatchContent.
+//
+// Globs without a path separator are matched against the base name of the file, while globs
+// with one, such as "conf/*.conf", are matched against the same number of trailing path
+// elements. Overrides added later take precedence over earlier ones.
+func (l *LexerRegistry) OverrideFilename(glob string, lexer Lexer) error {
+	if lexer == nil {
+		return fmt.Errorf("no lexer for filename override %q", glob)
+	}
+	glob = filepath.ToSlash(glob)
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid filename override %q: %w", glob, err)
+	}
+	l.overrides = append(l.overrides, filenameOverride{glob, lexer})
+	return nil
+}
+
+func (l *LexerRegistry) matchOverride(filename string) Lexer {
+	elements := strings.Split(filepath.ToSlash(filename), "/")
+	for i := len(l.overrides) - 1; i >= 0; i-- {
+		override := l.overrides[i]
+		n := strings.Count(override.glob, "/") + 1
+		if n > len(elements) {
+			continue
+		}
+		if ok, _ := path.Match(override.glob, strings.Join(elements[len(elements)-n:], "/")); ok {
+			return override.lexer
+		}
 	}
-	matched = nil
-	// Next, try filename aliases.
+	return nil
+}
+
+// matchFilename returns the lexers with a primary filename pattern, or an alias filename
+// pattern if aliases is true, matching filename.
+func (l *LexerRegistry) matchFilename(filename string, aliases bool) PrioritisedLexers {
+	matched := PrioritisedLexers{}
 	for _, lexer := range l.Lexers {
 		config := lexer.Config()
-		for _, glob := range config.AliasFilenames {
+		globs := config.Filenames
+		if aliases {
+			globs = config.AliasFilenames
+		}
+		for _, glob := range globs {
 			ok, err := filepath.Match(glob, filename)
 			if err != nil { // nolint
 				panic(err)
@@ -151,27 +215,66 @@ func (l *LexerRegistry) Match(filename string) Lexer {
 			}
 		}
 	}
-	if len(matched) > 0 {
-		sort.Sort(matched)
-		return matched[0]
-	}
-	return nil
+	return matched
 }
 
 // Analyse text content and return the "best" lexer..
 func (l *LexerRegistry) Analyse(text string) Lexer {
-	var picked Lexer
-	highest := float32(0.0)
+	candidates := l.AnalyseAll(text, 0)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0].Lexer
+}
+
+// TextClassifier identifies the language of text without the analysers of lexers, such as
+// lexers.Classifier.
+type TextClassifier interface {
+	// Analyse returns the most likely lexer for text, or nil.
+	Analyse(text string) Lexer
+}
+
+// AnalyseWith is an alternative to Analyse that picks the lexer for text with classifier, rather
+// than with the hand written analysers of the registered lexers.
+//
+// The registered lexer with the name of the classifier's pick is returned, or nil if there is
+// none.
+func (l *LexerRegistry) AnalyseWith(classifier TextClassifier, text string) Lexer {
+	picked := classifier.Analyse(text)
+	if picked == nil {
+		return nil
+	}
+	return l.byName[strings.ToLower(picked.Config().Name)]
+}
+
+// ScoredLexer is a Lexer and the score its analyser gave some text, between 0 and 1.
+type ScoredLexer struct {
+	Lexer Lexer
+	Score float32
+}
+
+// AnalyseAll returns the lexers whose analysers score text above zero and at least min,
+// highest score first.
+//
+// Analysers often give a low score to text they merely do not rule out, so callers can use min
+// to fall back to plain text rather than accept a weak guess:
+//
+//	candidates := registry.AnalyseAll(text, 0.5)
+//	if len(candidates) == 0 {
+//		// Use the fallback lexer.
+//	}
+func (l *LexerRegistry) AnalyseAll(text string, min float32) []ScoredLexer {
+	candidates := []ScoredLexer{}
 	for _, lexer := range l.Lexers {
 		if analyser, ok := lexer.(Analyser); ok {
-			weight := analyser.AnalyseText(text)
-			if weight > highest {
-				picked = lexer
-				highest = weight
+			if score := analyser.AnalyseText(text); score > 0 && score >= min {
+				candidates = append(candidates, ScoredLexer{Lexer: lexer, Score: score})
 			}
 		}
 	}
-	return picked
+	// Lexers with equal scores keep their registry order, so the first is the one Analyse picks.
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
 }
 
 // Register a Lexer with the LexerRegistry. If the lexer is already registered
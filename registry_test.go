@@ -0,0 +1,58 @@
+package chroma
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestOverrideFilename(t *testing.T) {
+	registry := NewLexerRegistry()
+	ini := registry.Register(mustNewLexer(t, &Config{Name: "INI", Filenames: []string{"*.conf", "*.ini"}}, Rules{"root": {}}))
+	nginx := registry.Register(mustNewLexer(t, &Config{Name: "Nginx", Filenames: []string{"nginx.conf"}}, Rules{"root": {}}))
+	apache := registry.Register(mustNewLexer(t, &Config{Name: "Apache"}, Rules{"root": {}}))
+
+	assert.Equal(t, ini, registry.Match("site.conf"))
+
+	assert.NoError(t, registry.OverrideFilename("*.conf", nginx))
+	assert.NoError(t, registry.OverrideFilename("apache/*.conf", apache))
+	assert.Equal(t, nginx, registry.Match("site.conf"))
+	assert.Equal(t, nginx, registry.Match("/etc/site.conf"))
+	assert.Equal(t, apache, registry.Match("/etc/apache/site.conf"))
+	assert.Equal(t, apache, registry.MatchContent("apache/site.conf", ""))
+	assert.Equal(t, ini, registry.Match("site.ini"))
+	assert.Equal(t, nginx, registry.Get("site.conf"))
+
+	assert.Error(t, registry.OverrideFilename("[", nginx))
+	assert.Error(t, registry.OverrideFilename("*.conf", nil))
+}
+
+func TestAnalyseAll(t *testing.T) {
+	registry := NewLexerRegistry()
+	score := func(name string, weight float32) Lexer {
+		return registry.Register(mustNewLexer(t, &Config{Name: name}, Rules{"root": {}}).SetAnalyser(func(text string) float32 {
+			return weight
+		}))
+	}
+	score("Weak", 0.01)
+	score("Strong", 0.8)
+	score("Medium", 0.5)
+	score("Tied", 0.5)
+	score("Zero", 0)
+	registry.Register(mustNewLexer(t, &Config{Name: "None"}, Rules{"root": {}}))
+
+	names := func(candidates []ScoredLexer) []string {
+		out := []string{}
+		for _, candidate := range candidates {
+			out = append(out, candidate.Lexer.Config().Name)
+		}
+		return out
+	}
+	candidates := registry.AnalyseAll("text", 0)
+	assert.Equal(t, []string{"Strong", "Medium", "Tied", "Weak"}, names(candidates))
+	assert.Equal(t, float32(0.8), candidates[0].Score)
+	assert.Equal(t, "Strong", registry.Analyse("text").Config().Name)
+
+	assert.Equal(t, []string{"Strong", "Medium", "Tied"}, names(registry.AnalyseAll("text", 0.5)))
+	assert.Equal(t, []string{}, names(registry.AnalyseAll("text", 0.9)))
+}
@@ -0,0 +1,215 @@
+// Package semantictokens converts Chroma token streams into LSP semantic tokens.
+//
+// The output of Encode is the delta-encoded "data" array of a textDocument/semanticTokens
+// response, and a Legend marshals to the SemanticTokensLegend a server advertises.
+package semantictokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// Encoding is the unit in which columns and lengths are measured.
+type Encoding int
+
+// Position encodings defined by LSP. UTF16 is the default required by the protocol.
+const (
+	UTF16 Encoding = iota
+	UTF8
+	UTF32
+)
+
+// Semantic is the LSP token type and modifiers a Chroma token type maps to.
+//
+// A Semantic with an empty Type suppresses tokens of the mapped type.
+type Semantic struct {
+	Type      string
+	Modifiers []string
+}
+
+// DefaultMapping maps Chroma token types to the standard LSP token types and modifiers.
+//
+// Token types without an entry use the mapping of their closest parent. Text, punctuation and
+// generic tokens are not mapped.
+var DefaultMapping = map[chroma.TokenType]Semantic{
+	chroma.Keyword:            {Type: "keyword"},
+	chroma.KeywordType:        {Type: "type"},
+	chroma.Name:               {Type: "variable"},
+	chroma.NameAttribute:      {Type: "property"},
+	chroma.NameBuiltin:        {Type: "variable", Modifiers: []string{"defaultLibrary"}},
+	chroma.NameBuiltinPseudo:  {Type: "variable", Modifiers: []string{"defaultLibrary"}},
+	chroma.NameClass:          {Type: "class"},
+	chroma.NameConstant:       {Type: "variable", Modifiers: []string{"readonly"}},
+	chroma.NameDecorator:      {Type: "decorator"},
+	chroma.NameEntity:         {},
+	chroma.NameException:      {Type: "class"},
+	chroma.NameFunction:       {Type: "function"},
+	chroma.NameFunctionMagic:  {Type: "function"},
+	chroma.NameKeyword:        {Type: "keyword"},
+	chroma.NameLabel:          {},
+	chroma.NameNamespace:      {Type: "namespace"},
+	chroma.NameOperator:       {Type: "operator"},
+	chroma.NameProperty:       {Type: "property"},
+	chroma.NameTag:            {},
+	chroma.Literal:            {},
+	chroma.LiteralString:      {Type: "string"},
+	chroma.LiteralStringDoc:   {Type: "string", Modifiers: []string{"documentation"}},
+	chroma.LiteralStringRegex: {Type: "regexp"},
+	chroma.LiteralNumber:      {Type: "number"},
+	chroma.Operator:           {Type: "operator"},
+	chroma.OperatorWord:       {Type: "keyword"},
+	chroma.Comment:            {Type: "comment"},
+	chroma.CommentPreproc:     {Type: "macro"},
+}
+
+// A Legend assigns indices to the token types and modifiers of a mapping.
+//
+// It marshals to an LSP SemanticTokensLegend.
+type Legend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+
+	mapping map[chroma.TokenType]entry
+}
+
+type entry struct {
+	skip      bool
+	tokenType uint32
+	modifiers uint32
+}
+
+// NewLegend creates a Legend from a mapping of Chroma token types.
+//
+// Token types and modifiers are listed in sorted order.
+func NewLegend(mapping map[chroma.TokenType]Semantic) (*Legend, error) {
+	typeSet := map[string]bool{}
+	modifierSet := map[string]bool{}
+	for _, semantic := range mapping {
+		if semantic.Type == "" {
+			continue
+		}
+		typeSet[semantic.Type] = true
+		for _, modifier := range semantic.Modifiers {
+			modifierSet[modifier] = true
+		}
+	}
+	if len(modifierSet) > 32 {
+		return nil, fmt.Errorf("too many token modifiers (%d), at most 32 are supported", len(modifierSet))
+	}
+	legend := &Legend{
+		TokenTypes:     sortedKeys(typeSet),
+		TokenModifiers: sortedKeys(modifierSet),
+		mapping:        map[chroma.TokenType]entry{},
+	}
+	typeIndex := indices(legend.TokenTypes)
+	modifierIndex := indices(legend.TokenModifiers)
+	for tokenType, semantic := range mapping {
+		if semantic.Type == "" {
+			legend.mapping[tokenType] = entry{skip: true}
+			continue
+		}
+		e := entry{tokenType: typeIndex[semantic.Type]}
+		for _, modifier := range semantic.Modifiers {
+			e.modifiers |= 1 << modifierIndex[modifier]
+		}
+		legend.mapping[tokenType] = e
+	}
+	return legend, nil
+}
+
+// MustNewLegend creates a Legend or panics.
+func MustNewLegend(mapping map[chroma.TokenType]Semantic) *Legend {
+	legend, err := NewLegend(mapping)
+	if err != nil {
+		panic(err)
+	}
+	return legend
+}
+
+// DefaultLegend is the Legend for DefaultMapping.
+var DefaultLegend = MustNewLegend(DefaultMapping)
+
+// lookup finds the entry for a token type or its closest mapped parent.
+func (l *Legend) lookup(tokenType chroma.TokenType) (entry, bool) {
+	for {
+		if e, ok := l.mapping[tokenType]; ok {
+			return e, !e.skip
+		}
+		if tokenType == 0 {
+			return entry{}, false
+		}
+		tokenType = tokenType.Parent()
+	}
+}
+
+// Encode converts the tokens produced by it into delta-encoded LSP semantic token data.
+//
+// Tokens spanning several lines are split into one semantic token per line, as not all
+// clients support multiline tokens. Tokens with no mapping in the legend are omitted.
+func Encode(legend *Legend, encoding Encoding, it chroma.Iterator) []uint32 {
+	data := []uint32{}
+	var line, column, prevLine, prevColumn uint32
+	emit := func(length uint32, e entry) {
+		deltaColumn := column
+		if line == prevLine {
+			deltaColumn = column - prevColumn
+		}
+		data = append(data, line-prevLine, deltaColumn, length, e.tokenType, e.modifiers)
+		prevLine, prevColumn = line, column
+	}
+	for token := it(); token != chroma.EOF; token = it() {
+		e, ok := legend.lookup(token.Type)
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				line++
+				column = 0
+			}
+			length := encodedLength(strings.TrimSuffix(part, "\r"), encoding)
+			if ok && length > 0 {
+				emit(length, e)
+			}
+			column += encodedLength(part, encoding)
+		}
+	}
+	return data
+}
+
+func encodedLength(s string, encoding Encoding) uint32 {
+	switch encoding {
+	case UTF8:
+		return uint32(len(s))
+	case UTF32:
+		return uint32(utf8.RuneCountInString(s))
+	default:
+		n := 0
+		for _, r := range s {
+			if r >= 0x10000 {
+				n += 2
+			} else {
+				n++
+			}
+		}
+		return uint32(n)
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for key := range set {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func indices(names []string) map[string]uint32 {
+	out := make(map[string]uint32, len(names))
+	for i, name := range names {
+		out[name] = uint32(i)
+	}
+	return out
+}
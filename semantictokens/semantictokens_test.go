@@ -0,0 +1,89 @@
+package semantictokens
+
+import (
+	"encoding/json"
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+func TestNewLegend(t *testing.T) {
+	legend, err := NewLegend(map[chroma.TokenType]Semantic{
+		chroma.Keyword:      {Type: "keyword"},
+		chroma.NameFunction: {Type: "function", Modifiers: []string{"declaration"}},
+		chroma.NameBuiltin:  {Type: "function", Modifiers: []string{"defaultLibrary"}},
+		chroma.NameTag:      {},
+	})
+	assert.NoError(t, err)
+	data, err := json.Marshal(legend)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"tokenTypes":["function","keyword"],"tokenModifiers":["declaration","defaultLibrary"]}`, string(data))
+}
+
+func TestEncode(t *testing.T) {
+	tokens := []chroma.Token{
+		{Type: chroma.Keyword, Value: "func"},
+		{Type: chroma.TextWhitespace, Value: " "},
+		{Type: chroma.NameFunction, Value: "f"},
+		{Type: chroma.Punctuation, Value: "() {\n\t"},
+		{Type: chroma.NameBuiltinPseudo, Value: "nil"},
+		{Type: chroma.Punctuation, Value: "\n}\n"},
+	}
+	data := Encode(DefaultLegend, UTF16, chroma.Literator(tokens...))
+	keyword := index(DefaultLegend.TokenTypes, "keyword")
+	function := index(DefaultLegend.TokenTypes, "function")
+	variable := index(DefaultLegend.TokenTypes, "variable")
+	defaultLibrary := uint32(1) << index(DefaultLegend.TokenModifiers, "defaultLibrary")
+	assert.Equal(t, []uint32{
+		0, 0, 4, keyword, 0,
+		0, 5, 1, function, 0,
+		1, 1, 3, variable, defaultLibrary,
+	}, data)
+}
+
+func TestEncodeMultilineToken(t *testing.T) {
+	tokens := []chroma.Token{
+		{Type: chroma.TextWhitespace, Value: "  "},
+		{Type: chroma.CommentMultiline, Value: "/* a\r\n\r\n  bc */"},
+	}
+	comment := index(DefaultLegend.TokenTypes, "comment")
+	data := Encode(DefaultLegend, UTF16, chroma.Literator(tokens...))
+	assert.Equal(t, []uint32{
+		0, 2, 4, comment, 0,
+		2, 0, 7, comment, 0,
+	}, data)
+}
+
+func TestEncodePositionEncodings(t *testing.T) {
+	tokens := []chroma.Token{
+		{Type: chroma.LiteralString, Value: `"é😀"`},
+		{Type: chroma.Operator, Value: "+"},
+	}
+	str := index(DefaultLegend.TokenTypes, "string")
+	operator := index(DefaultLegend.TokenTypes, "operator")
+	for _, test := range []struct {
+		encoding Encoding
+		length   uint32
+	}{
+		{UTF16, 5},
+		{UTF8, 8},
+		{UTF32, 4},
+	} {
+		data := Encode(DefaultLegend, test.encoding, chroma.Literator(tokens...))
+		assert.Equal(t, []uint32{
+			0, 0, test.length, str, 0,
+			0, test.length, 1, operator, 0,
+		}, data)
+	}
+}
+
+func index(names []string, name string) uint32 {
+	for i, n := range names {
+		if n == name {
+			return uint32(i)
+		}
+	}
+	panic(name)
+}
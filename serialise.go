@@ -74,6 +74,7 @@ var (
 		out := map[string]SerialisableMutator{}
 		for _, mutator := range []SerialisableMutator{
 			&includeMutator{},
+			&longestMatchMutator{},
 			&combinedMutator{},
 			&multiMutator{},
 			&pushMutator{},
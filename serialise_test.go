@@ -46,6 +46,7 @@ func TestMutatorSerialisationRoundTrip(t *testing.T) {
 		{"Multi", Mutators(Include("string").Mutator, Push("quote"))},
 		{"Push", Push("include")},
 		{"Pop", Pop(1)},
+		{"LongestMatch", LongestMatch().Mutator},
 	}
 	for _, test := range tests {
 		// nolint: scopelint
@@ -0,0 +1,64 @@
+package chroma
+
+import (
+	"sort"
+)
+
+// TokenStatistics are the number of tokens of each type in a document.
+type TokenStatistics struct {
+	// Number of tokens of each type.
+	Counts map[TokenType]int
+	// Total number of tokens.
+	Total int
+}
+
+// NewTokenStatistics counts the tokens of each type in a token stream. Empty tokens are ignored.
+func NewTokenStatistics(it Iterator) *TokenStatistics {
+	stats := &TokenStatistics{Counts: map[TokenType]int{}}
+	for token := it(); token != EOF; token = it() {
+		if token.Value == "" {
+			continue
+		}
+		stats.Counts[token.Type]++
+		stats.Total++
+	}
+	return stats
+}
+
+// Ratio of tokens that are of the given type, from 0.0 to 1.0.
+func (s *TokenStatistics) Ratio(tokenType TokenType) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Counts[tokenType]) / float64(s.Total)
+}
+
+// CategoryRatio is the ratio of tokens in the same category as the given type, such as all
+// Keyword or Comment tokens.
+func (s *TokenStatistics) CategoryRatio(tokenType TokenType) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	n := 0
+	for t, count := range s.Counts {
+		if t.InCategory(tokenType) {
+			n += count
+		}
+	}
+	return float64(n) / float64(s.Total)
+}
+
+// Types returns the token types that occur, most frequent first.
+func (s *TokenStatistics) Types() []TokenType {
+	types := make([]TokenType, 0, len(s.Counts))
+	for t := range s.Counts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if s.Counts[types[i]] != s.Counts[types[j]] {
+			return s.Counts[types[i]] > s.Counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
@@ -0,0 +1,24 @@
+package chroma
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestTokenStatistics(t *testing.T) {
+	stats := NewTokenStatistics(Literator(
+		Token{Type: Keyword, Value: "if"},
+		Token{Type: Text, Value: " "},
+		Token{Type: KeywordConstant, Value: "true"},
+		Token{Type: Text, Value: " "},
+		Token{Type: Comment, Value: "// yes"},
+		Token{Type: Text, Value: ""},
+	))
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, 0.4, stats.Ratio(Text))
+	assert.Equal(t, 0.2, stats.Ratio(Keyword))
+	assert.Equal(t, 0.4, stats.CategoryRatio(Keyword))
+	assert.Equal(t, []TokenType{Text, Keyword, KeywordConstant, Comment}, stats.Types())
+	assert.Equal(t, 0.0, NewTokenStatistics(Literator()).Ratio(Text))
+}
@@ -120,3 +120,54 @@ func TestStyleMarshaller(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 }
+
+func TestStyleTransforms(t *testing.T) {
+	style, err := NewStyle("test", StyleEntries{
+		Background: "#e0e0e0 bg:#202020",
+		Keyword:    "bold #ff4040",
+		Comment:    "#808080 border:#404040",
+	})
+	assert.NoError(t, err)
+
+	dim, err := style.Builder().Transform(Dim(0.5)).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#a00000", dim.Get(Keyword).Colour.String())
+	assert.Equal(t, Yes, dim.Get(Keyword).Bold)
+	assert.Equal(t, "#101010", dim.Get(Background).Background.String())
+
+	contrast, err := style.Builder().Transform(IncreaseContrast(0.5)).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#f0f0f0", contrast.Get(Background).Colour.String())
+	assert.Equal(t, "#101010", contrast.Get(Background).Background.String())
+	assert.Equal(t, "#202020", contrast.Get(Comment).Border.String())
+
+	grey, err := style.Builder().Transform(Desaturate(1)).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#a0a0a0", grey.Get(Keyword).Colour.String())
+
+	inverted, err := style.Builder().Transform(InvertLightness).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#1f1f1f", inverted.Get(Background).Colour.String())
+	assert.Equal(t, "#dfdfdf", inverted.Get(Background).Background.String())
+	assert.Equal(t, "#bf0000", inverted.Get(Keyword).Colour.String())
+
+	// The original style should be unchanged.
+	assert.Equal(t, "#ff4040", style.Get(Keyword).Colour.String())
+}
+
+func TestStyleBuilderAdaptTo(t *testing.T) {
+	style, err := NewStyle("test", StyleEntries{
+		Background: "#e0e0e0 bg:#202020",
+		Keyword:    "#ff4040",
+	})
+	assert.NoError(t, err)
+
+	dark, err := style.Builder().AdaptTo(true).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#202020", dark.Get(Background).Background.String())
+
+	light, err := style.Builder().AdaptTo(false).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "#dfdfdf", light.Get(Background).Background.String())
+	assert.Equal(t, "#bf0000", light.Get(Keyword).Colour.String())
+}
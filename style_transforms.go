@@ -0,0 +1,72 @@
+package chroma
+
+// Ready-made transforms for StyleBuilder.Transform, for adapting a single style at runtime,
+// such as to the background of the user's terminal.
+
+// Dim darkens every colour of a style entry by reducing its lightness by amount, in the range
+// 0.0 to 1.0.
+func Dim(amount float64) func(StyleEntry) StyleEntry {
+	amount = clampUnit(amount)
+	return mapEntryColours(func(c Colour) Colour {
+		_, _, l := c.HSL()
+		return c.WithLightness(l * (1 - amount))
+	})
+}
+
+// IncreaseContrast pushes the lightness of every colour of a style entry away from mid-grey by
+// amount, in the range 0.0 to 1.0, so that dark colours get darker and light colours lighter.
+func IncreaseContrast(amount float64) func(StyleEntry) StyleEntry {
+	amount = clampUnit(amount)
+	return mapEntryColours(func(c Colour) Colour {
+		_, _, l := c.HSL()
+		if l < 0.5 {
+			return c.WithLightness(l * (1 - amount))
+		}
+		return c.WithLightness(l + (1-l)*amount)
+	})
+}
+
+// Desaturate reduces the saturation of every colour of a style entry by amount, in the range
+// 0.0 to 1.0, where 1.0 produces greys.
+func Desaturate(amount float64) func(StyleEntry) StyleEntry {
+	amount = clampUnit(amount)
+	return mapEntryColours(func(c Colour) Colour {
+		_, s, _ := c.HSL()
+		return c.WithSaturation(s * (1 - amount))
+	})
+}
+
+// InvertLightness inverts the lightness of every colour of a style entry while keeping its hue,
+// turning a dark style into a light one and vice versa.
+func InvertLightness(entry StyleEntry) StyleEntry {
+	return mapEntryColours(func(c Colour) Colour {
+		_, _, l := c.HSL()
+		return c.WithLightness(1 - l)
+	})(entry)
+}
+
+// AdaptTo inverts the lightness of the style with InvertLightness if its background does not
+// match a dark or light terminal background. Styles without a background are left unchanged.
+func (s *StyleBuilder) AdaptTo(dark bool) *StyleBuilder {
+	background := s.Get(Background).Background
+	if !background.IsSet() || (background.Brightness() < 0.5) == dark {
+		return s
+	}
+	return s.Transform(InvertLightness)
+}
+
+// mapEntryColours returns a transform applying fn to each colour set in a style entry.
+func mapEntryColours(fn func(Colour) Colour) func(StyleEntry) StyleEntry {
+	return func(entry StyleEntry) StyleEntry {
+		if entry.Colour.IsSet() {
+			entry.Colour = fn(entry.Colour)
+		}
+		if entry.Background.IsSet() {
+			entry.Background = fn(entry.Background)
+		}
+		if entry.Border.IsSet() {
+			entry.Border = fn(entry.Border)
+		}
+		return entry
+	}
+}
@@ -0,0 +1,68 @@
+package styles
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// IsDark returns true if the style has a dark background.
+func IsDark(style *chroma.Style) bool {
+	background := style.Get(chroma.Background).Background
+	return background.IsSet() && background.Brightness() < 0.5
+}
+
+// Variant returns the named style, or Fallback, in a variant suited to a dark or light
+// background.
+//
+// If the style does not suit the background, the registered style of the same family that
+// does is returned, such as "solarized-light" for "solarized-dark", or "catppuccin-latte" for
+// "catppuccin-mocha". Styles without such a sibling are inverted with chroma.InvertLightness.
+// Styles without a background suit either, and are returned unchanged.
+func Variant(name string, dark bool) *chroma.Style {
+	style := Get(name)
+	if !style.Get(chroma.Background).Background.IsSet() || IsDark(style) == dark {
+		return style
+	}
+	family := styleFamily(style.Name)
+	candidates := []string{}
+	for _, candidate := range Names() {
+		if candidate != style.Name && styleFamily(candidate) == family && IsDark(Registry[candidate]) == dark {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) > 0 {
+		// Prefer the sibling sharing the longest prefix, so "solarized-dark256" becomes
+		// "solarized-light" rather than any other member of the family.
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return commonPrefixLen(style.Name, candidates[i]) > commonPrefixLen(style.Name, candidates[j])
+		})
+		return Registry[candidates[0]]
+	}
+	inverted, err := style.Builder().Transform(chroma.InvertLightness).Build()
+	if err != nil {
+		return style
+	}
+	return inverted
+}
+
+// styleFamily is the name of a style up to its first hyphen, without any "dark" or "light"
+// suffix, such that "monokai" and "monokailight" are the same family.
+func styleFamily(name string) string {
+	family := strings.SplitN(name, "-", 2)[0]
+	for _, suffix := range []string{"dark", "light"} {
+		if trimmed := strings.TrimSuffix(family, suffix); trimmed != "" {
+			family = trimmed
+		}
+	}
+	return family
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,40 @@
+package styles
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+)
+
+func TestVariant(t *testing.T) {
+	tests := []struct {
+		name  string
+		dark  bool
+		style string
+	}{
+		{"solarized-dark", true, "solarized-dark"},
+		{"solarized-dark", false, "solarized-light"},
+		{"solarized-dark256", false, "solarized-light"},
+		{"solarized-light", true, "solarized-dark"},
+		{"github", true, "github-dark"},
+		{"monokai", false, "monokailight"},
+		{"catppuccin-mocha", false, "catppuccin-latte"},
+		{"modus-vivendi", false, "modus-operandi"},
+		{"rose-pine-moon", false, "rose-pine-dawn"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			style := Variant(test.name, test.dark)
+			assert.Equal(t, test.style, style.Name)
+			assert.Equal(t, test.dark, IsDark(style))
+		})
+	}
+}
+
+func TestVariantInverted(t *testing.T) {
+	assert.True(t, IsDark(Get("dracula")))
+	style := Variant("dracula", false)
+	assert.False(t, IsDark(style))
+	// The registered style is unchanged.
+	assert.True(t, IsDark(Get("dracula")))
+}
@@ -0,0 +1,102 @@
+// Package symbols extracts declarations from Chroma token streams, providing a lightweight,
+// ctags-like outline of source code.
+//
+// Declarations are found by token type alone, so the results are only as precise as the lexer
+// used: some lexers also tag function calls as NameFunction, for example.
+package symbols
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2"
+)
+
+// DefaultTypes are the token types of declarations extracted by default.
+var DefaultTypes = []chroma.TokenType{chroma.NameFunction, chroma.NameFunctionMagic, chroma.NameClass}
+
+// A Symbol is a declaration in source code.
+type Symbol struct {
+	Name string           `json:"name"`
+	Type chroma.TokenType `json:"type"`
+	// 1-based line and column, in characters, of the start of the name.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Byte offset of the start of the name.
+	Offset int `json:"offset"`
+	// Indentation of the line declaring the symbol, in characters.
+	Indent int `json:"indent"`
+	// Symbols declared within this one, such as methods of a class.
+	Children []*Symbol `json:"children,omitempty"`
+}
+
+// Extract returns the declarations in a token stream, as a flat list in source order.
+//
+// Tokens of any of the given types, or of DefaultTypes if none are given, are declarations.
+func Extract(it chroma.Iterator, types ...chroma.TokenType) []*Symbol {
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	var out []*Symbol
+	line, column, offset, indent := 1, 1, 0, 0
+	lineStart := true
+	for token := it(); token != chroma.EOF; token = it() {
+		if name := strings.TrimSpace(token.Value); name != "" && isDeclaration(token.Type, types) {
+			leading := token.Value[:strings.Index(token.Value, name)]
+			out = append(out, &Symbol{
+				Name:   name,
+				Type:   token.Type,
+				Line:   line,
+				Column: column + utf8.RuneCountInString(leading),
+				Offset: offset + len(leading),
+				Indent: indent,
+			})
+		}
+		for _, r := range token.Value {
+			switch {
+			case r == '\n':
+				line++
+				column = 1
+				indent = 0
+				lineStart = true
+			case lineStart && (r == ' ' || r == '\t'):
+				indent++
+				column++
+			default:
+				lineStart = false
+				column++
+			}
+		}
+		offset += len(token.Value)
+	}
+	return out
+}
+
+// Outline nests symbols by indentation, so that each symbol is a child of the closest preceding
+// symbol declared on a less indented line, and returns the top-level symbols.
+func Outline(symbols []*Symbol) []*Symbol {
+	var roots []*Symbol
+	var stack []*Symbol
+	for _, symbol := range symbols {
+		for len(stack) > 0 && stack[len(stack)-1].Indent >= symbol.Indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, symbol)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, symbol)
+		}
+		stack = append(stack, symbol)
+	}
+	return roots
+}
+
+func isDeclaration(tokenType chroma.TokenType, types []chroma.TokenType) bool {
+	for _, t := range types {
+		if tokenType == t {
+			return true
+		}
+	}
+	return false
+}
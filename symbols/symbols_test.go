@@ -0,0 +1,51 @@
+package symbols
+
+import (
+	"testing"
+
+	assert "github.com/alecthomas/assert/v2"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+const source = `class Foo:
+    def __init__(self):
+        pass
+
+    def bar(self):
+        return baz(1)
+
+def qux():
+    pass
+`
+
+func TestExtract(t *testing.T) {
+	it, err := lexers.Get("python").Tokenise(nil, source)
+	assert.NoError(t, err)
+	symbols := Extract(it)
+	assert.Equal(t, []*Symbol{
+		{Name: "Foo", Type: chroma.NameClass, Line: 1, Column: 7, Offset: 6, Indent: 0},
+		{Name: "__init__", Type: chroma.NameFunctionMagic, Line: 2, Column: 9, Offset: 19, Indent: 4},
+		{Name: "bar", Type: chroma.NameFunction, Line: 5, Column: 9, Offset: 57, Indent: 4},
+		{Name: "qux", Type: chroma.NameFunction, Line: 8, Column: 5, Offset: 95, Indent: 0},
+	}, symbols)
+
+	it, err = lexers.Get("python").Tokenise(nil, source)
+	assert.NoError(t, err)
+	symbols = Extract(it, chroma.NameClass)
+	assert.Equal(t, 1, len(symbols))
+	assert.Equal(t, "Foo", symbols[0].Name)
+}
+
+func TestOutline(t *testing.T) {
+	it, err := lexers.Get("python").Tokenise(nil, source)
+	assert.NoError(t, err)
+	outline := Outline(Extract(it))
+	assert.Equal(t, 2, len(outline))
+	assert.Equal(t, "Foo", outline[0].Name)
+	assert.Equal(t, 2, len(outline[0].Children))
+	assert.Equal(t, "bar", outline[0].Children[1].Name)
+	assert.Equal(t, "qux", outline[1].Name)
+	assert.Equal(t, 0, len(outline[1].Children))
+}
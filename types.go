@@ -336,8 +336,15 @@ func (t TokenType) InSubCategory(other TokenType) bool {
 	return t/100 == other/100
 }
 
-func (t TokenType) Emit(groups []string, _ *LexerState) Iterator {
-	return Literator(Token{Type: t, Value: groups[0]})
+func (t TokenType) Emit(groups []string, state *LexerState) Iterator {
+	token := Token{Type: t, Value: groups[0]}
+	if state != nil && state.Lexer != nil {
+		var ok bool
+		if token, ok = state.Lexer.postProcess(token); !ok {
+			return Literator()
+		}
+	}
+	return Literator(token)
 }
 
 func (t TokenType) EmitterKind() string { return "token" }